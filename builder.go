@@ -0,0 +1,129 @@
+package fixdecoder
+
+import "strconv"
+
+// FieldValue a {{fieldId}}={{value}} pair of a message to build
+type FieldValue struct {
+	FieldID string
+	Value   string
+}
+
+// MessageBuilder build a FIX message. Header fields are emitted first and trailer fields last whatever the order they are set in,
+// and BodyLength <9> and CheckSum <10> are computed. For example:
+//
+//	fixdecoder.NewMessageBuilder("FIX.4.4", "D").SetField("49", "CLIENT").SetField("56", "BROKER").SetField("11", "ORD1").Bytes()
+type MessageBuilder struct {
+	beginString string
+	msgType     string
+	entries     [][]FieldValue // Single fields, or count fields followed by the fields of their group
+}
+
+// NewMessageBuilder new message builder instance
+func NewMessageBuilder(beginString, msgType string) *MessageBuilder {
+	return &MessageBuilder{beginString: beginString, msgType: msgType}
+}
+
+// SetField set the value of a header, body or trailer field, replacing the previous value if any.
+// BeginString <8> and MsgType <35> replace the ones given to NewMessageBuilder; BodyLength <9> and CheckSum <10> are ignored as they are computed
+func (b *MessageBuilder) SetField(fieldID, value string) *MessageBuilder {
+	switch fieldID {
+	case BEGINSTRING:
+		b.beginString = value
+		return b
+	case MSGTYPE:
+		b.msgType = value
+		return b
+	case BODYLENGTH, CHECKSUM:
+		return b
+	}
+
+	for _, entry := range b.entries {
+		if len(entry) == 1 && entry[0].FieldID == fieldID {
+			entry[0].Value = value
+			return b
+		}
+	}
+
+	b.entries = append(b.entries, []FieldValue{{FieldID: fieldID, Value: value}})
+	return b
+}
+
+// AddGroup add a repeating group: its count field (e.g. 453 NoPartyIDs), set to the number of instances, followed by the fields of every instance.
+// The fields of a nested group are part of the instance, its count field included
+func (b *MessageBuilder) AddGroup(countFieldID string, instances ...[]FieldValue) *MessageBuilder {
+	entry := []FieldValue{{FieldID: countFieldID, Value: strconv.Itoa(len(instances))}}
+	for _, instance := range instances {
+		entry = append(entry, instance...)
+	}
+
+	b.entries = append(b.entries, entry)
+	return b
+}
+
+// Bytes the message, SOH delimited, in header, body and trailer order, with BodyLength <9> and CheckSum <10> computed the way
+// BodyLengthValidator and CheckSumValidator check them
+func (b *MessageBuilder) Bytes() []byte {
+	dictionary := SelectDictionary(b.beginString, b.applVerID(), "9")
+
+	sections := make([][]FieldValue, 3)
+	for _, entry := range b.entries {
+		section := 1
+		if dictionary.Header != nil && b.isMember(dictionary.Header, entry[0].FieldID) {
+			section = 0
+		} else if dictionary.Trailer != nil && b.isMember(dictionary.Trailer, entry[0].FieldID) {
+			section = 2
+		}
+
+		sections[section] = append(sections[section], entry...)
+	}
+
+	body := make([]byte, 0, 256)
+	body = appendField(body, MSGTYPE, b.msgType)
+	for _, section := range sections {
+		for _, field := range section {
+			body = appendField(body, field.FieldID, field.Value)
+		}
+	}
+
+	message := make([]byte, 0, len(body)+32)
+	message = appendField(message, BEGINSTRING, b.beginString)
+	message = appendField(message, BODYLENGTH, strconv.Itoa(len(body)))
+	message = append(message, body...)
+
+	sum := 0
+	for _, c := range message {
+		sum += int(c)
+	}
+
+	return appendField(message, CHECKSUM, formatCheckSum(sum))
+}
+
+// String the message, SOH delimited
+func (b *MessageBuilder) String() string {
+	return string(b.Bytes())
+}
+
+// applVerID the ApplVerID <1128> set, if any
+func (b *MessageBuilder) applVerID() string {
+	for _, entry := range b.entries {
+		if entry[0].FieldID == APPLVERID {
+			return entry[0].Value
+		}
+	}
+
+	return ""
+}
+
+// isMember whether a field belongs to the header or the trailer
+func (b *MessageBuilder) isMember(definition *Definition, fieldID string) bool {
+	_, contain := contains(definition.Fields, fieldID)
+	return contain
+}
+
+// appendField append {{fieldId}}={{value}}<SOH>
+func appendField(data []byte, fieldID, value string) []byte {
+	data = append(data, fieldID...)
+	data = append(data, '=')
+	data = append(data, value...)
+	return append(data, SOH...)
+}
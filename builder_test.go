@@ -0,0 +1,68 @@
+package fixdecoder_test
+
+import (
+	"strings"
+	"testing"
+
+	fixdecoder "github.com/ilovelili/FixDecoder"
+)
+
+func TestMessageBuilder(t *testing.T) {
+	actual := fixdecoder.NewMessageBuilder("FIX.4.4", "2").
+		SetField("49", "CNX").
+		SetField("34", "8263336").
+		SetField("52", "20180126-07:39:59.683").
+		SetField("56", "imdstream").
+		SetField("16", "0").
+		SetField("7", "12812").
+		String()
+
+	if actual != validfixmessage {
+		t.Errorf("expect %q, actual %q", validfixmessage, actual)
+	}
+}
+
+func TestMessageBuilder_Groups(t *testing.T) {
+	message := fixdecoder.NewMessageBuilder("FIX.4.4", "D").
+		SetField("11", "ORD1").
+		AddGroup("453",
+			[]fixdecoder.FieldValue{{FieldID: "448", Value: "BRKR"}, {FieldID: "447", Value: "D"}, {FieldID: "452", Value: "1"}},
+			[]fixdecoder.FieldValue{{FieldID: "448", Value: "CLNT"}, {FieldID: "447", Value: "D"}, {FieldID: "452", Value: "3"}},
+		).
+		SetField("55", "IBM").
+		SetField("49", "CLIENT").
+		SetField("56", "BROKER").
+		SetField("11", "ORD2").
+		String()
+
+	msg, err := fd.DecodeE(message)
+	if err != nil {
+		t.Fatalf("expect no error, actual %v", err)
+	}
+
+	// header fields first, whatever the order they were set in
+	if expect, actual := "8,9,35,49,56,11,453", fieldIDs(msg.Fields[:7]); actual != expect {
+		t.Errorf("expect %s, actual %s", expect, actual)
+	}
+
+	if expect, actual := "ORD2", msg.Fields[5].Value; actual != expect {
+		t.Errorf("expect %s, actual %s", expect, actual)
+	}
+
+	if len(msg.Groups) != 1 || len(msg.Groups[0].Instances) != 2 {
+		t.Errorf("expect 1 group of 2 instances, actual %v", msg.Groups)
+	}
+
+	if output := msg.String(); strings.Contains(output, "Invalid") {
+		t.Errorf("expect valid message, actual %s", output)
+	}
+}
+
+func fieldIDs(dfs fixdecoder.DecodedFields) string {
+	ids := make([]string, 0, len(dfs))
+	for _, df := range dfs {
+		ids = append(ids, df.FieldID)
+	}
+
+	return strings.Join(ids, ",")
+}
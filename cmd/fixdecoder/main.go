@@ -0,0 +1,288 @@
+// Command fixdecoder decode FIX messages given as arguments, in files or on stdin, one message per line:
+//
+//	fixdecoder [--format table|json|raw] [--validate] [--profile venue.json] [--delimiter '|'] [--file path]... [message]...
+//
+// With --validate, the exit status is 1 if the BodyLength or CheckSum of a message is invalid.
+//
+//	fixdecoder tail [--comp-id ME] [--from-start] engine.log
+//
+// follows an engine log like tail -f, and prints a one-line summary of every FIX message written to it
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	fixdecoder "github.com/ilovelili/FixDecoder"
+)
+
+// maxLineSize lines longer than this are not decoded
+const maxLineSize = 4 * 1024 * 1024
+
+// exit statuses
+const (
+	exitOK      = 0
+	exitInvalid = 1
+	exitError   = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// fileList files given by repeated --file flags
+type fileList []string
+
+func (l *fileList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *fileList) Set(path string) error {
+	*l = append(*l, path)
+	return nil
+}
+
+// printer print a decoded message and its validation issues
+type printer func(w io.Writer, dfs fixdecoder.DecodedFields, issues []*fixdecoder.ValidationIssue) error
+
+// printers printers by output format
+var printers = map[string]printer{
+	"table": printTable,
+	"json":  printJSON,
+	"raw":   printRaw,
+}
+
+// command state of a fixdecoder run
+type command struct {
+	decoder   *fixdecoder.FixDecoder
+	extractor *fixdecoder.Extractor
+	printer   printer
+	validate  bool
+	stdout    io.Writer
+	stderr    io.Writer
+	invalid   int // Number of messages with an invalid BodyLength or CheckSum
+}
+
+// run run the command, and return its exit status
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) > 0 && args[0] == "tail" {
+		return runTail(args[1:], stdout, stderr)
+	}
+
+	flags := flag.NewFlagSet("fixdecoder", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	format := flags.String("format", "table", "output format: table, json or raw")
+	validate := flags.Bool("validate", false, "exit with status 1 if the BodyLength or CheckSum of a message is invalid")
+	delimiter := flags.String("delimiter", "", "field delimiter, e.g. '|' or '^A'; detected from every message if not set")
+	profile := flags.String("profile", "", "JSON validation profile, e.g. the rules of a counterparty")
+	spec := flags.String("spec", "", "directory of QuickFIX dictionaries (FIX42.xml, FIX50SP2.xml, FIXT11.xml...) replacing the built-in ones")
+	showVersion := flags.Bool("version", false, "print the version and exit")
+	var files fileList
+	flags.Var(&files, "file", "decode the messages of a file, one per line; can be repeated")
+
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	if *showVersion {
+		fmt.Fprintln(stdout, fixdecoder.Version())
+		return exitOK
+	}
+
+	printer, found := printers[*format]
+	if !found {
+		fmt.Fprintf(stderr, "fixdecoder: unknown format %s\n", *format)
+		return exitError
+	}
+
+	if *spec != "" {
+		if _, err := fixdecoder.LoadQuickFIXVersionDictionaries(*spec); err != nil {
+			fmt.Fprintf(stderr, "fixdecoder: %v\n", err)
+			return exitError
+		}
+	}
+
+	options := make([]fixdecoder.Option, 0)
+	if *delimiter != "" {
+		options = append(options, fixdecoder.WithDelimiter(*delimiter))
+	}
+
+	if *profile != "" {
+		p, err := fixdecoder.LoadValidationProfileFile(*profile)
+		if err != nil {
+			fmt.Fprintf(stderr, "fixdecoder: %v\n", err)
+			return exitError
+		}
+		options = append(options, fixdecoder.WithValidationProfile(p))
+	}
+
+	c := &command{
+		decoder:   fixdecoder.NewFixDecoder(options...),
+		extractor: fixdecoder.NewExtractor(options...),
+		printer:   printer,
+		validate:  *validate,
+		stdout:    stdout,
+		stderr:    stderr,
+	}
+
+	for i, message := range flags.Args() {
+		if err := c.decode(message, fmt.Sprintf("argument %d", i+1)); err != nil {
+			fmt.Fprintf(stderr, "fixdecoder: %v\n", err)
+			return exitError
+		}
+	}
+
+	for _, path := range files {
+		if err := c.decodeFile(path); err != nil {
+			fmt.Fprintf(stderr, "fixdecoder: %v\n", err)
+			return exitError
+		}
+	}
+
+	if len(flags.Args()) == 0 && len(files) == 0 {
+		if err := c.decodeLines(stdin, "stdin"); err != nil {
+			fmt.Fprintf(stderr, "fixdecoder: %v\n", err)
+			return exitError
+		}
+	}
+
+	if c.validate && c.invalid > 0 {
+		return exitInvalid
+	}
+
+	return exitOK
+}
+
+// decodeFile decode the messages of a file
+func (c *command) decodeFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return c.decodeLines(file, path)
+}
+
+// decodeLines decode the messages of a reader, one per line
+func (c *command) decodeLines(r io.Reader, source string) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	for line := 1; scanner.Scan(); line++ {
+		if err := c.decode(scanner.Text(), fmt.Sprintf("%s:%d", source, line)); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+// decode decode and print the messages of a line, ignoring the text around them, e.g. log timestamps.
+// Lines without any 8=FIX are decoded as a whole, and skipped if they have no field
+func (c *command) decode(line, source string) error {
+	messages := c.extractor.Extract(line)
+	if messages == nil {
+		return c.print(c.stdout, c.decoder.Decode(line), source)
+	}
+
+	for _, message := range messages {
+		if err := c.print(c.stdout, message.Fields, source); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// print validate and print a message
+func (c *command) print(w io.Writer, dfs fixdecoder.DecodedFields, source string) error {
+	if len(dfs) == 0 {
+		return nil
+	}
+
+	issues := c.decoder.Validate(dfs)
+	if !valid(issues) {
+		c.invalid++
+		if c.validate {
+			fmt.Fprintf(c.stderr, "fixdecoder: invalid BodyLength or CheckSum in %s\n", source)
+		}
+	}
+
+	return c.printer(w, dfs, issues)
+}
+
+// valid tell whether BodyLength and CheckSum are valid, or missing. Issues downgraded to warnings by a profile do not count,
+// nor issues of other validators about these fields, e.g. their position
+func valid(issues []*fixdecoder.ValidationIssue) bool {
+	for _, issue := range issues {
+		if issue.Severity != fixdecoder.ERROR {
+			continue
+		}
+
+		switch issue.Code {
+		case fixdecoder.INVALIDBODYLENGTH, fixdecoder.INVALIDCHECKSUM:
+			return false
+		case fixdecoder.MISSINGFIELD:
+			if issue.Tag == 9 || issue.Tag == 10 {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// printTable one row per field, one table per message
+func printTable(w io.Writer, dfs fixdecoder.DecodedFields, issues []*fixdecoder.ValidationIssue) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "TAG\tNAME\tVALUE\tDECODED")
+	for _, df := range dfs {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", df.FieldID, df.Field.Name, df.Value, df.Describe(issues))
+	}
+	fmt.Fprintln(tw)
+
+	return tw.Flush()
+}
+
+// jsonField a field in json output
+type jsonField struct {
+	ID           string
+	Name         string
+	Value        string
+	DecodedValue string `json:",omitempty"`
+}
+
+// printJSON one json array of fields per line
+func printJSON(w io.Writer, dfs fixdecoder.DecodedFields, issues []*fixdecoder.ValidationIssue) error {
+	fields := make([]jsonField, 0, len(dfs))
+	for _, df := range dfs {
+		fields = append(fields, jsonField{ID: df.FieldID, Name: df.Field.Name, Value: df.Value, DecodedValue: df.Describe(issues)})
+	}
+
+	binary, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "%s\n", binary)
+	return err
+}
+
+// printRaw the fields, '|' delimited, one message per line
+func printRaw(w io.Writer, dfs fixdecoder.DecodedFields, issues []*fixdecoder.ValidationIssue) error {
+	fields := make([]string, 0, len(dfs))
+	for _, df := range dfs {
+		fields = append(fields, df.FieldID+"="+df.Value)
+	}
+
+	_, err := fmt.Fprintf(w, "%s|\n", strings.Join(fields, "|"))
+	return err
+}
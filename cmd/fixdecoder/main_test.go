@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	fixdecoder "github.com/ilovelili/FixDecoder"
+)
+
+const (
+	validfixmessage   = "8=FIX.4.4|9=74|35=2|49=CNX|34=8263336|52=20180126-07:39:59.683|56=imdstream|16=0|7=12812|10=036|"
+	invalidfixmessage = "8=FIX.4.4|9=74|35=2|49=CNX|34=8263336|52=20180126-07:39:59.683|56=imdstream|16=0|7=12812|10=999|"
+)
+
+func TestRun(t *testing.T) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	stdin := strings.NewReader(validfixmessage + "\n\n" + validfixmessage + "\n")
+
+	if status := run([]string{"--format", "raw", "--validate"}, stdin, stdout, stderr); status != exitOK {
+		t.Fatalf("expect status %d, actual %d: %s", exitOK, status, stderr)
+	}
+
+	if expect, actual := validfixmessage+"\n"+validfixmessage+"\n", stdout.String(); actual != expect {
+		t.Errorf("expect %s, actual %s", expect, actual)
+	}
+
+	stdout.Reset()
+	if status := run([]string{"--format", "json", validfixmessage}, nil, stdout, stderr); status != exitOK {
+		t.Fatalf("expect status %d, actual %d: %s", exitOK, status, stderr)
+	}
+
+	if expect := `{"ID":"35","Name":"MsgType","Value":"2","DecodedValue":"Resend Request"}`; !strings.Contains(stdout.String(), expect) {
+		t.Errorf("expect %s in %s", expect, stdout)
+	}
+}
+
+func TestRun_Validate(t *testing.T) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+
+	if status := run([]string{"--validate", validfixmessage, invalidfixmessage}, nil, stdout, stderr); status != exitInvalid {
+		t.Errorf("expect status %d, actual %d", exitInvalid, status)
+	}
+
+	if expect := "argument 2"; !strings.Contains(stderr.String(), expect) {
+		t.Errorf("expect %s in %s", expect, stderr)
+	}
+
+	if expect := "Invalid (expected 036)"; !strings.Contains(stdout.String(), expect) {
+		t.Errorf("expect %s in %s", expect, stdout)
+	}
+
+	nobodylength := strings.Replace(validfixmessage, "9=74|", "", 1)
+	if status := run([]string{"--validate", nobodylength}, nil, stdout, stderr); status != exitInvalid {
+		t.Errorf("expect status %d without BodyLength, actual %d", exitInvalid, status)
+	}
+
+	if status := run([]string{"--format", "xml", validfixmessage}, nil, stdout, stderr); status != exitError {
+		t.Errorf("expect status %d, actual %d", exitError, status)
+	}
+}
+
+func TestValid(t *testing.T) {
+	misplaced := &fixdecoder.ValidationIssue{Tag: 10, Severity: fixdecoder.ERROR, Code: fixdecoder.MISPLACEDFIELD}
+	if !valid([]*fixdecoder.ValidationIssue{misplaced}) {
+		t.Error("expect a misplaced CheckSum to be valid")
+	}
+
+	checksum := &fixdecoder.ValidationIssue{Tag: 10, Severity: fixdecoder.ERROR, Code: fixdecoder.INVALIDCHECKSUM}
+	if valid([]*fixdecoder.ValidationIssue{misplaced, checksum}) {
+		t.Error("expect an invalid CheckSum to be invalid")
+	}
+
+	checksum.Severity = fixdecoder.WARNING
+	if !valid([]*fixdecoder.ValidationIssue{checksum}) {
+		t.Error("expect an invalid CheckSum downgraded to a warning to be valid")
+	}
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	fixdecoder "github.com/ilovelili/FixDecoder"
+)
+
+// tailReadSize number of bytes read from the log at once
+const tailReadSize = 64 * 1024
+
+// summaryFields fields of the one-line summary, by tag
+var summaryFields = []struct {
+	fieldID string
+	label   string
+}{
+	{"11", "ClOrdID"},
+	{"55", "Symbol"},
+	{"54", "Side"},
+	{"38", "Qty"},
+	{"44", "Px"},
+	{"39", "OrdStatus"},
+}
+
+// runTail follow a log file like tail -f, and print a one-line summary of every FIX message written to it
+//
+//	fixdecoder tail [--comp-id ME] [--from-start] [--interval 500ms] [--spec quickfix/spec] engine.log
+func runTail(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("fixdecoder tail", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	compID := flags.String("comp-id", "", "our CompID: messages it sends are OUT, the others IN. Without it, the direction is SenderCompID->TargetCompID")
+	fromStart := flags.Bool("from-start", false, "print the messages already in the file first")
+	interval := flags.Duration("interval", 500*time.Millisecond, "how often the file is checked for new lines, rotation and truncation")
+	delimiter := flags.String("delimiter", "", "field delimiter, e.g. '|' or '^A'; detected from every message if not set")
+	spec := flags.String("spec", "", "directory of QuickFIX dictionaries (FIX42.xml, FIX50SP2.xml, FIXT11.xml...) replacing the built-in ones")
+
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	if flags.NArg() != 1 {
+		fmt.Fprintln(stderr, "usage: fixdecoder tail [flags] file")
+		return exitError
+	}
+
+	if *spec != "" {
+		if _, err := fixdecoder.LoadQuickFIXVersionDictionaries(*spec); err != nil {
+			fmt.Fprintf(stderr, "fixdecoder: %v\n", err)
+			return exitError
+		}
+	}
+
+	options := make([]fixdecoder.Option, 0)
+	if *delimiter != "" {
+		options = append(options, fixdecoder.WithDelimiter(*delimiter))
+	}
+	decoder := fixdecoder.NewFixDecoder(options...)
+	extractor := fixdecoder.NewExtractor(options...)
+
+	stop := make(chan struct{})
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	go func() {
+		<-signals
+		close(stop)
+	}()
+
+	f := &follower{path: flags.Arg(0), interval: *interval, fromStart: *fromStart}
+	err := f.follow(stop, func(line string) {
+		for _, message := range extractor.Extract(line) {
+			fmt.Fprintln(stdout, summarize(decoder, message, *compID))
+		}
+	})
+
+	if err != nil {
+		fmt.Fprintf(stderr, "fixdecoder: %v\n", err)
+		return exitError
+	}
+
+	return exitOK
+}
+
+// summarize one-line summary of a message extracted from a log line: time, direction, MsgType and the main order fields.
+// The message is named after the dictionary decoder picks for it
+func summarize(decoder *fixdecoder.FixDecoder, message *fixdecoder.ExtractedMessage, compID string) string {
+	fields := make(map[string]*fixdecoder.DecodedField)
+	for _, df := range message.Fields {
+		if _, found := fields[df.FieldID]; !found {
+			fields[df.FieldID] = df
+		}
+	}
+
+	value := func(fieldID string) string {
+		if df, found := fields[fieldID]; found {
+			return df.Value
+		}
+		return ""
+	}
+
+	// SendingTime, or the timestamp of the log line
+	timestamp := value("52")
+	if timestamp == "" {
+		timestamp = message.Timestamp
+	}
+
+	direction := message.Direction
+	if direction == "" {
+		direction = value("49") + "->" + value("56")
+	}
+
+	if compID != "" {
+		direction = "IN"
+		if value("49") == compID {
+			direction = "OUT"
+		}
+	}
+
+	// the message name of the dictionary, e.g. NewOrderSingle, or else the MsgType enum name
+	msgType := decodedValue(fields["35"])
+	if definition := decoder.SelectDictionary(value("8"), value("1128")).Message(value("35")); definition != nil {
+		msgType = definition.Name
+	}
+
+	parts := []string{timestamp, direction, msgType}
+	for _, field := range summaryFields {
+		if df, found := fields[field.fieldID]; found {
+			parts = append(parts, field.label+"="+decodedValue(df))
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// decodedValue the enum name of a value if it has one, e.g. Buy for Side <54> 1, else the value
+func decodedValue(df *fixdecoder.DecodedField) string {
+	switch {
+	case df == nil:
+		return "?"
+	case df.DecodedValue != "":
+		return strings.Replace(df.DecodedValue, " ", "", -1)
+	}
+
+	return df.Value
+}
+
+// follower read the lines appended to a file, reopening it when it is rotated and reading it again from the start when it is truncated
+type follower struct {
+	path      string
+	interval  time.Duration
+	fromStart bool // Whether the lines already in the file are read, or only the new ones
+
+	file    *os.File
+	info    os.FileInfo // Of the opened file, to detect rotation
+	offset  int64
+	pending []byte // Incomplete last line
+}
+
+// follow call handle for every line appended to the file, until stop is closed
+func (f *follower) follow(stop <-chan struct{}, handle func(line string)) error {
+	defer f.close()
+
+	buffer := make([]byte, tailReadSize)
+	for {
+		if err := f.poll(buffer, handle); err != nil {
+			return err
+		}
+
+		select {
+		case <-stop:
+			return nil
+		case <-time.After(f.interval):
+		}
+	}
+}
+
+// poll read what was appended since the last poll, then check for rotation and truncation
+func (f *follower) poll(buffer []byte, handle func(line string)) error {
+	if f.file == nil {
+		if err := f.open(); os.IsNotExist(err) {
+			// rotated and not created again yet: the new file will be read from its start
+			f.fromStart = true
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+
+	for {
+		n, err := f.file.Read(buffer)
+		f.offset += int64(n)
+		f.lines(buffer[:n], handle)
+
+		if err == io.EOF || n == 0 {
+			break
+		} else if err != nil {
+			return err
+		}
+	}
+
+	info, err := os.Stat(f.path)
+	switch {
+	case os.IsNotExist(err) || (err == nil && !os.SameFile(f.info, info)):
+		// rotated: the new file is read from its start, the last line of the old one will not be completed
+		f.flush(handle)
+		f.close()
+		f.fromStart = true
+	case err != nil:
+		return err
+	case info.Size() < f.offset:
+		// truncated: read again from the start
+		if _, err := f.file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		f.flush(handle)
+		f.offset = 0
+	}
+
+	return nil
+}
+
+// open open the file, at its end unless fromStart
+func (f *follower) open() error {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	f.file, f.info, f.offset = file, info, 0
+	if !f.fromStart {
+		f.offset, err = file.Seek(0, io.SeekEnd)
+	}
+
+	return err
+}
+
+// close close the file
+func (f *follower) close() {
+	if f.file != nil {
+		f.file.Close()
+		f.file = nil
+	}
+}
+
+// lines call handle for every complete line of data, keeping the incomplete last one for later
+func (f *follower) lines(data []byte, handle func(line string)) {
+	f.pending = append(f.pending, data...)
+	for {
+		end := bytes.IndexByte(f.pending, '\n')
+		if end < 0 {
+			return
+		}
+
+		handle(strings.TrimRight(string(f.pending[:end]), "\r"))
+		f.pending = f.pending[end+1:]
+	}
+}
+
+// flush call handle for the incomplete last line, if any
+func (f *follower) flush(handle func(line string)) {
+	if len(f.pending) > 0 {
+		handle(strings.TrimRight(string(f.pending), "\r"))
+		f.pending = nil
+	}
+}
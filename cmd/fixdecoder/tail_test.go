@@ -0,0 +1,93 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	fixdecoder "github.com/ilovelili/FixDecoder"
+)
+
+const neworder = "8=FIX.4.4|9=0|35=D|49=CLIENT|56=BROKER|52=20180126-07:39:59.683|11=ORD1|55=IBM|54=1|38=100|44=12.5|40=2|10=000|"
+
+func TestSummarize(t *testing.T) {
+	message := fixdecoder.NewExtractor().Extract("20180126-07:39:59.690 : " + neworder)[0]
+
+	expect := "20180126-07:39:59.683 OUT NewOrderSingle ClOrdID=ORD1 Symbol=IBM Side=Buy Qty=100 Px=12.5"
+	if actual := summarize(fixdecoder.NewFixDecoder(), message, "CLIENT"); actual != expect {
+		t.Errorf("expect %s, actual %s", expect, actual)
+	}
+
+	expect = "20180126-07:39:59.683 CLIENT->BROKER NewOrderSingle ClOrdID=ORD1 Symbol=IBM Side=Buy Qty=100 Px=12.5"
+	if actual := summarize(fixdecoder.NewFixDecoder(), message, ""); actual != expect {
+		t.Errorf("expect %s, actual %s", expect, actual)
+	}
+
+	// named after the dictionary of the decoder
+	dictionary := fixdecoder.NewDictionary("FIX.4.4")
+	dictionary.Messages["D"] = &fixdecoder.Definition{Name: "VenueOrder"}
+	decoder := fixdecoder.NewFixDecoder(fixdecoder.WithDictionary(dictionary))
+	if actual := summarize(decoder, message, "CLIENT"); !strings.Contains(actual, " OUT VenueOrder ") {
+		t.Errorf("expect VenueOrder, actual %s", actual)
+	}
+}
+
+func TestFollower(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "messages.log")
+	if err := os.WriteFile(path, []byte("old line\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := make([]string, 0)
+	f := &follower{path: path}
+	buffer := make([]byte, 16)
+	poll := func() {
+		if err := f.poll(buffer, func(line string) { lines = append(lines, line) }); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	poll()
+	appendFile(t, path, "first\nsec")
+	poll()
+	appendFile(t, path, "ond\n")
+	poll()
+
+	// truncated
+	if err := os.WriteFile(path, []byte("third\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	poll()
+	poll()
+
+	// rotated
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	appendFile(t, path+".1", "fourth\nhalf")
+	poll()
+	appendFile(t, path, "fifth\n")
+	poll()
+	f.close()
+
+	if expect, actual := "first,second,third,fourth,half,fifth", strings.Join(lines, ","); actual != expect {
+		t.Errorf("expect %s, actual %s", expect, actual)
+	}
+}
+
+func appendFile(t *testing.T, path, data string) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(data); err != nil {
+		t.Fatal(err)
+	}
+
+	// make sure modifications are seen even on coarse grained file systems
+	time.Sleep(time.Millisecond)
+}
@@ -0,0 +1,97 @@
+// Command fixdictgen generate the built-in dictionaries of the FIX versions from the QuickFIX data dictionaries of a spec directory
+// (FIX40.xml to FIX50SP2.xml, FIXT11.xml):
+//
+//	fixdictgen --spec quickfix/spec --output versions_data.go
+//
+// FIX44.xml is skipped, the FIX 4.4 dictionary is protocol.go. Run by go generate, see make dictionaries
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	fixdecoder "github.com/ilovelili/FixDecoder"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
+
+// run run the command, and return its exit status
+func run(args []string, stderr io.Writer) int {
+	flags := flag.NewFlagSet("fixdictgen", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	spec := flags.String("spec", "quickfix/spec", "directory of the QuickFIX dictionaries")
+	output := flags.String("output", "versions_data.go", "generated Go file")
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	paths, err := filepath.Glob(filepath.Join(*spec, "FIX*.xml"))
+	if err == nil && len(paths) == 0 {
+		err = fmt.Errorf("no QuickFIX dictionary in %s", *spec)
+	}
+
+	var source []byte
+	if err == nil {
+		source, err = generate(paths)
+	}
+
+	if err == nil {
+		err = os.WriteFile(*output, source, 0644)
+	}
+
+	if err != nil {
+		fmt.Fprintf(stderr, "fixdictgen: %v\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+// generate the Go source setting the versionData of the fixdecoder package from QuickFIX dictionary files
+func generate(paths []string) ([]byte, error) {
+	data := make(map[string][]byte)
+	for _, path := range paths {
+		d, err := fixdecoder.LoadQuickFIXDictionaryFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+
+		if d.Version == "FIX.4.4" {
+			continue
+		}
+
+		if data[d.Version], err = d.JSON(); err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+	}
+
+	versions := make([]string, 0, len(data))
+	for version := range data {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+
+	var b bytes.Buffer
+	fmt.Fprintln(&b, "// Code generated by fixdictgen from the QuickFIX data dictionaries. DO NOT EDIT.")
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "package fixdecoder")
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "func init() {")
+	for _, version := range versions {
+		fmt.Fprintf(&b, "versionData[%q] = %s\n", version, strconv.Quote(string(data[version])))
+	}
+	fmt.Fprintln(&b, "}")
+
+	return format.Source(b.Bytes())
+}
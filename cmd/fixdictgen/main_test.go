@@ -0,0 +1,49 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const spec = `<fix type="FIX" major="4" minor="2">
+ <header><field name="BeginString" required="Y"/><field name="BodyLength" required="Y"/><field name="MsgType" required="Y"/></header>
+ <trailer><field name="CheckSum" required="Y"/></trailer>
+ <messages>
+  <message name="Heartbeat" msgtype="0" msgcat="admin"><field name="TestReqID" required="N"/></message>
+ </messages>
+ <components/>
+ <fields>
+  <field number="8" name="BeginString" type="STRING"/>
+  <field number="9" name="BodyLength" type="LENGTH"/>
+  <field number="10" name="CheckSum" type="STRING"/>
+  <field number="35" name="MsgType" type="STRING"><value enum="0" description="HEARTBEAT"/></field>
+  <field number="112" name="TestReqID" type="STRING"/>
+ </fields>
+</fix>`
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "FIX42.xml"), []byte(spec), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	output := filepath.Join(dir, "versions_data.go")
+	if status := run([]string{"--spec", dir, "--output", output}, os.Stderr); status != 0 {
+		t.Fatalf("expect 0, actual %d", status)
+	}
+
+	source, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect, actual := `versionData["FIX.4.2"] = "{`, string(source); !strings.Contains(actual, expect) || !strings.Contains(actual, `TestReqID`) {
+		t.Errorf("expect %s, actual %s", expect, actual)
+	}
+
+	if status := run([]string{"--spec", t.TempDir(), "--output", output}, os.Stderr); status != 1 {
+		t.Errorf("expect 1, actual %d", status)
+	}
+}
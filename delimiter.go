@@ -0,0 +1,92 @@
+package fixdecoder
+
+import "bytes"
+
+// Delimiters commonly found in FIX messages and logs
+const (
+	SOH        = "\x01"  // ascii SOH, the delimiter of FIX messages on the wire
+	PIPE       = "|"     // SOH substituted by a pipe, e.g. in engine logs
+	CARETSOH   = "^A"    // SOH in caret notation, e.g. copied from a terminal
+	LITERALSOH = "<SOH>" // SOH written out, e.g. in documentation or tickets
+)
+
+// WithDelimiter decode messages whose fields are delimited by the given delimiter only, e.g. SOH, PIPE, CARETSOH, LITERALSOH or any custom one.
+// Other delimiter-like bytes, such as ';' in a Text <58>, are then part of the values
+func WithDelimiter(delimiter string) Option {
+	return func(f *FixDecoder) {
+		f.delimiter = delimiter
+	}
+}
+
+// WithAutoDelimiter infer the delimiter of each message from what follows its BeginString <8>, see DetectDelimiter. This is the default
+func WithAutoDelimiter() Option {
+	return func(f *FixDecoder) {
+		f.delimiter = ""
+	}
+}
+
+// DetectDelimiter the delimiter following the BeginString <8> of a message, e.g. "\x01" for 8=FIX.4.4\x01 or "^A" for 8=FIX.4.4^A.
+// Empty if the message has no BeginString, in which case SOH, '|' and ';' are all delimiters
+func DetectDelimiter(message []byte) string {
+	start := bytes.Index(message, beginStringPrefix)
+	if start < 0 {
+		return ""
+	}
+
+	// FIX.4.4, FIXT.1.1...
+	p := start + len("8=")
+	for p < len(message) && isBeginStringByte(message[p]) {
+		p++
+	}
+
+	rest := message[p:]
+	switch {
+	case len(rest) == 0:
+		return ""
+	case bytes.HasPrefix(rest, []byte(LITERALSOH)):
+		return LITERALSOH
+	case bytes.HasPrefix(rest, []byte(CARETSOH)):
+		return CARETSOH
+	}
+
+	return string(rest[:1])
+}
+
+// isBeginStringByte whether c may be part of a BeginString <8> value
+func isBeginStringByte(c byte) bool {
+	return isDigit(c) || c == '.' || (c >= 'A' && c <= 'Z')
+}
+
+// delimiterAt the length of the delimiter at data[i], 0 if there is none. An empty delimiter stands for any of SOH, '|' and ';'
+func delimiterAt(data []byte, i int, delimiter string) int {
+	if delimiter == "" {
+		if i < len(data) && isDelimiter(data[i]) {
+			return 1
+		}
+		return 0
+	}
+
+	if len(data)-i >= len(delimiter) && string(data[i:i+len(delimiter)]) == delimiter {
+		return len(delimiter)
+	}
+
+	return 0
+}
+
+// nextDelimiter the offset and length of the first delimiter at or after data[start]; len(data) and 0 if there is none
+func nextDelimiter(data []byte, start int, delimiter string) (int, int) {
+	if len(delimiter) == 1 {
+		if i := bytes.IndexByte(data[start:], delimiter[0]); i >= 0 {
+			return start + i, 1
+		}
+		return len(data), 0
+	}
+
+	for i := start; i < len(data); i++ {
+		if n := delimiterAt(data, i, delimiter); n > 0 {
+			return i, n
+		}
+	}
+
+	return len(data), 0
+}
@@ -0,0 +1,343 @@
+package fixdecoder
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/tidwall/gjson"
+)
+
+// FieldDefinition definition of a field
+type FieldDefinition struct {
+	Tag              string
+	Number           int // Tag as a number
+	Name             string
+	Type             string
+	Values           map[string]string // Enumerated values and their description
+	AllowOtherValues bool              // Whether values outside of Values are accepted
+	IsHeaderField    bool
+	IsRequired       bool
+	DeprecatedSince  string
+	LengthField      string // For DATA fields, the tag of the LENGTH field giving their size
+
+	metaData *FieldMetaData // Shared by the fields decoded with this definition, set by AddField
+}
+
+// GroupDefinition definition of a repeating group. The first field is the delimiter: it starts every instance of the group
+type GroupDefinition struct {
+	Fields []string
+}
+
+// Definition definition of the header, the trailer, a component or a message: its fields in order, the required ones,
+// and the components it contains
+type Definition struct {
+	Name               string
+	Fields             []string
+	Required           []string
+	Components         []string
+	RequiredComponents []string
+}
+
+// Dictionary a FIX data dictionary
+type Dictionary struct {
+	Version        string                      // e.g. FIX.4.4
+	Fields         map[string]*FieldDefinition // group by tag
+	Groups         map[string]*GroupDefinition // group by the tag of the NUMINGROUP count field
+	Components     map[string]*Definition      // group by component name
+	Messages       map[string]*Definition      // group by MsgType <35>
+	Header         *Definition
+	Trailer        *Definition
+	SystemFieldIDs []string
+
+	fieldsByName        map[string]*FieldDefinition
+	fieldsByNumber      []*FieldDefinition       // index of the fields with a tag up to maxIndexedTag
+	fieldsByLargeNumber map[int]*FieldDefinition // index of the other ones
+}
+
+// maxIndexedTag fields with a tag up to this are indexed in a slice, the others in a map
+const maxIndexedTag = 1 << 16
+
+var (
+	defaultDictionary     *Dictionary
+	defaultDictionaryOnce sync.Once
+)
+
+// DefaultDictionary the built-in FIX 4.4 dictionary
+func DefaultDictionary() *Dictionary {
+	defaultDictionaryOnce.Do(func() {
+		defaultDictionary, _ = LoadJSONDictionary(fix)
+		defaultDictionary.Version = "FIX.4.4"
+	})
+
+	return defaultDictionary
+}
+
+// NewDictionary new empty dictionary
+func NewDictionary(version string) *Dictionary {
+	return &Dictionary{
+		Version:        version,
+		Fields:         make(map[string]*FieldDefinition),
+		Groups:         make(map[string]*GroupDefinition),
+		Components:     make(map[string]*Definition),
+		Messages:       make(map[string]*Definition),
+		Header:         &Definition{Name: "Header"},
+		Trailer:        &Definition{Name: "Trailer"},
+		SystemFieldIDs: []string{CHECKSUM},
+		fieldsByName:   make(map[string]*FieldDefinition),
+
+		fieldsByLargeNumber: make(map[int]*FieldDefinition),
+	}
+}
+
+// LoadJSONDictionary load a dictionary in the JSON format of the built-in one (see protocol.go)
+func LoadJSONDictionary(data string) (*Dictionary, error) {
+	if !gjson.Valid(data) {
+		return nil, fmt.Errorf("fixdecoder: invalid JSON dictionary")
+	}
+
+	root := gjson.Parse(data)
+	d := NewDictionary("")
+
+	d.SystemFieldIDs = jsonStrings(root.Get("systemFieldIds"))
+	root.Get("fieldsByTag").ForEach(func(tag, field gjson.Result) bool {
+		definition := &FieldDefinition{
+			Tag:              tag.String(),
+			Name:             field.Get("name").String(),
+			Type:             field.Get("type").String(),
+			AllowOtherValues: field.Get("allowOtherValues").Bool(),
+			IsHeaderField:    field.Get("isHeaderField").Bool(),
+			IsRequired:       field.Get("isRequired").Bool(),
+			DeprecatedSince:  field.Get("deprecatedSince").String(),
+		}
+
+		if values := field.Get("values"); values.Exists() {
+			definition.Values = make(map[string]string)
+			values.ForEach(func(value, description gjson.Result) bool {
+				definition.Values[value.String()] = description.String()
+				return true
+			})
+		}
+
+		d.AddField(definition)
+		return true
+	})
+
+	root.Get("groupsByTag").ForEach(func(tag, group gjson.Result) bool {
+		d.Groups[tag.String()] = &GroupDefinition{Fields: jsonStrings(group.Get("fields"))}
+		return true
+	})
+
+	root.Get("componentsByName").ForEach(func(name, component gjson.Result) bool {
+		d.Components[name.String()] = jsonDefinition(name.String(), component)
+		return true
+	})
+
+	root.Get("messagesByType").ForEach(func(msgType, message gjson.Result) bool {
+		d.Messages[msgType.String()] = jsonDefinition(message.Get("name").String(), message)
+		return true
+	})
+
+	if header := root.Get("header"); header.Exists() {
+		d.Header = jsonDefinition("Header", header)
+	}
+
+	if trailer := root.Get("trailer"); trailer.Exists() {
+		d.Trailer = jsonDefinition("Trailer", trailer)
+	}
+
+	d.linkDataFields()
+	return d, nil
+}
+
+// jsonField field definition in the JSON format of the built-in dictionary
+type jsonField struct {
+	Name             string            `json:"name"`
+	Type             string            `json:"type"`
+	Values           map[string]string `json:"values,omitempty"`
+	AllowOtherValues bool              `json:"allowOtherValues,omitempty"`
+	IsHeaderField    bool              `json:"isHeaderField,omitempty"`
+	IsRequired       bool              `json:"isRequired,omitempty"`
+	DeprecatedSince  string            `json:"deprecatedSince,omitempty"`
+}
+
+// jsonGroup repeating group definition in the JSON format of the built-in dictionary
+type jsonGroup struct {
+	Fields []string `json:"fields"`
+}
+
+// jsonDefinitionData header, trailer, component or message definition in the JSON format of the built-in dictionary
+type jsonDefinitionData struct {
+	Name               string   `json:"name,omitempty"`
+	Fields             []string `json:"fields"`
+	Required           []string `json:"required"`
+	Components         []string `json:"components,omitempty"`
+	RequiredComponents []string `json:"requiredComponents,omitempty"`
+}
+
+// jsonDictionary dictionary in the JSON format of the built-in one
+type jsonDictionary struct {
+	SystemFieldIDs   []string                       `json:"systemFieldIds"`
+	FieldsByTag      map[string]jsonField           `json:"fieldsByTag"`
+	GroupsByTag      map[string]jsonGroup           `json:"groupsByTag"`
+	Header           *jsonDefinitionData            `json:"header"`
+	Trailer          *jsonDefinitionData            `json:"trailer"`
+	ComponentsByName map[string]*jsonDefinitionData `json:"componentsByName"`
+	MessagesByType   map[string]*jsonDefinitionData `json:"messagesByType"`
+}
+
+// JSON the dictionary in the JSON format of the built-in one, to be loaded back with LoadJSONDictionary
+func (d *Dictionary) JSON() ([]byte, error) {
+	definition := func(definition *Definition, name string) *jsonDefinitionData {
+		return &jsonDefinitionData{
+			Name:               name,
+			Fields:             definition.Fields,
+			Required:           definition.Required,
+			Components:         definition.Components,
+			RequiredComponents: definition.RequiredComponents,
+		}
+	}
+
+	data := jsonDictionary{
+		SystemFieldIDs:   d.SystemFieldIDs,
+		FieldsByTag:      make(map[string]jsonField, len(d.Fields)),
+		GroupsByTag:      make(map[string]jsonGroup, len(d.Groups)),
+		Header:           definition(d.Header, ""),
+		Trailer:          definition(d.Trailer, ""),
+		ComponentsByName: make(map[string]*jsonDefinitionData, len(d.Components)),
+		MessagesByType:   make(map[string]*jsonDefinitionData, len(d.Messages)),
+	}
+
+	for tag, field := range d.Fields {
+		data.FieldsByTag[tag] = jsonField{
+			Name:             field.Name,
+			Type:             field.Type,
+			Values:           field.Values,
+			AllowOtherValues: field.AllowOtherValues,
+			IsHeaderField:    field.IsHeaderField,
+			IsRequired:       field.IsRequired,
+			DeprecatedSince:  field.DeprecatedSince,
+		}
+	}
+
+	for tag, group := range d.Groups {
+		data.GroupsByTag[tag] = jsonGroup{Fields: group.Fields}
+	}
+
+	for name, component := range d.Components {
+		data.ComponentsByName[name] = definition(component, "")
+	}
+
+	for msgType, message := range d.Messages {
+		data.MessagesByType[msgType] = definition(message, message.Name)
+	}
+
+	return json.Marshal(data)
+}
+
+// AddField add or replace a field definition. Fields must be added with AddField rather than directly to Fields to be indexed,
+// and to capture the Name and Type reported by the fields decoded with the definition
+func (d *Dictionary) AddField(field *FieldDefinition) {
+	if previous, found := d.Fields[field.Tag]; found {
+		delete(d.fieldsByName, previous.Name)
+	}
+
+	if field.Number == 0 {
+		field.Number, _ = strconv.Atoi(field.Tag)
+	}
+
+	if field.metaData == nil || field.metaData.Name != field.Name || field.metaData.Type != field.Type {
+		field.metaData = &FieldMetaData{Name: field.Name, Type: field.Type}
+	}
+
+	d.Fields[field.Tag] = field
+	if field.Name != "" {
+		d.fieldsByName[field.Name] = field
+	}
+
+	switch {
+	case field.Number <= 0:
+		// not a valid tag number, not indexed
+	case field.Number <= maxIndexedTag:
+		if field.Number >= len(d.fieldsByNumber) {
+			fieldsByNumber := make([]*FieldDefinition, field.Number+1, 2*field.Number+1)
+			copy(fieldsByNumber, d.fieldsByNumber)
+			d.fieldsByNumber = fieldsByNumber
+		}
+		d.fieldsByNumber[field.Number] = field
+	default:
+		d.fieldsByLargeNumber[field.Number] = field
+	}
+}
+
+// FieldByTag get a field definition by tag number, nil if not found. Unlike Field, it does not allocate nor hash
+func (d *Dictionary) FieldByTag(tag int) *FieldDefinition {
+	if tag >= 0 && tag < len(d.fieldsByNumber) {
+		return d.fieldsByNumber[tag]
+	}
+
+	return d.fieldsByLargeNumber[tag]
+}
+
+// Field get a field definition by tag, nil if not found
+func (d *Dictionary) Field(tag string) *FieldDefinition {
+	return d.Fields[tag]
+}
+
+// FieldByName get a field definition by name, nil if not found
+func (d *Dictionary) FieldByName(name string) *FieldDefinition {
+	return d.fieldsByName[name]
+}
+
+// Group get the definition of the repeating group introduced by a NUMINGROUP field, nil if not found
+func (d *Dictionary) Group(tag string) *GroupDefinition {
+	return d.Groups[tag]
+}
+
+// Message get a message definition by MsgType <35>, nil if not found
+func (d *Dictionary) Message(msgType string) *Definition {
+	return d.Messages[msgType]
+}
+
+// linkDataFields pair every DATA field with the LENGTH field giving its size: the one named after it (e.g. SignatureLength <93> for Signature <89>),
+// or else the one right before it
+func (d *Dictionary) linkDataFields() {
+	for _, field := range d.Fields {
+		if field.Type != "DATA" || field.LengthField != "" {
+			continue
+		}
+
+		for _, suffix := range []string{"Len", "Length"} {
+			if length := d.FieldByName(field.Name + suffix); length != nil && length.Type == "LENGTH" {
+				field.LengthField = length.Tag
+				break
+			}
+		}
+
+		if length := d.FieldByTag(field.Number - 1); field.LengthField == "" && length != nil && length.Type == "LENGTH" {
+			field.LengthField = length.Tag
+		}
+	}
+}
+
+// jsonStrings JSON array to string slice
+func jsonStrings(array gjson.Result) []string {
+	result := make([]string, 0)
+	for _, item := range array.Array() {
+		result = append(result, item.String())
+	}
+
+	return result
+}
+
+// jsonDefinition JSON header, trailer, component or message definition
+func jsonDefinition(name string, definition gjson.Result) *Definition {
+	return &Definition{
+		Name:               name,
+		Fields:             jsonStrings(definition.Get("fields")),
+		Required:           jsonStrings(definition.Get("required")),
+		Components:         jsonStrings(definition.Get("components")),
+		RequiredComponents: jsonStrings(definition.Get("requiredComponents")),
+	}
+}
@@ -0,0 +1,217 @@
+package fixdecoder_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	fixdecoder "github.com/ilovelili/FixDecoder"
+)
+
+const quickfixdictionary = `<fix type="FIX" major="4" minor="4" servicepack="0">
+ <header>
+  <field name="BeginString" required="Y"/>
+  <field name="BodyLength" required="Y"/>
+  <field name="MsgType" required="Y"/>
+ </header>
+ <trailer>
+  <field name="CheckSum" required="Y"/>
+ </trailer>
+ <messages>
+  <message name="NewOrderSingle" msgtype="D" msgcat="app">
+   <field name="ClOrdID" required="Y"/>
+   <component name="Parties" required="N"/>
+   <field name="Side" required="Y"/>
+   <field name="VenueOrderFlag" required="N"/>
+  </message>
+ </messages>
+ <components>
+  <component name="Parties">
+   <group name="NoPartyIDs" required="N">
+    <field name="PartyID" required="N"/>
+    <field name="PartyRole" required="N"/>
+   </group>
+  </component>
+ </components>
+ <fields>
+  <field number="8" name="BeginString" type="STRING"/>
+  <field number="9" name="BodyLength" type="LENGTH"/>
+  <field number="10" name="CheckSum" type="STRING"/>
+  <field number="11" name="ClOrdID" type="STRING"/>
+  <field number="35" name="MsgType" type="STRING">
+   <value enum="D" description="ORDER_SINGLE"/>
+  </field>
+  <field number="54" name="Side" type="CHAR">
+   <value enum="1" description="BUY"/>
+   <value enum="2" description="SELL"/>
+  </field>
+  <field number="448" name="PartyID" type="STRING"/>
+  <field number="452" name="PartyRole" type="INT"/>
+  <field number="453" name="NoPartyIDs" type="NUMINGROUP"/>
+  <field number="5001" name="VenueOrderFlag" type="CHAR">
+   <value enum="Y" description="FLAGGED"/>
+  </field>
+ </fields>
+</fix>`
+
+func TestLoadQuickFIXDictionary(t *testing.T) {
+	dictionary, err := fixdecoder.LoadQuickFIXDictionary(strings.NewReader(quickfixdictionary))
+	if err != nil {
+		t.Fatalf("expect no error, actual %v", err)
+	}
+
+	if dictionary.Version != "FIX.4.4" {
+		t.Errorf("expect FIX.4.4, actual %s", dictionary.Version)
+	}
+
+	if expect, actual := "448,452", strings.Join(dictionary.Group("453").Fields, ","); actual != expect {
+		t.Errorf("expect group members %s, actual %s", expect, actual)
+	}
+
+	if expect, actual := "11,54,5001", strings.Join(dictionary.Message("D").Fields, ","); actual != expect {
+		t.Errorf("expect message fields %s, actual %s", expect, actual)
+	}
+
+	decoder := fixdecoder.NewFixDecoder(fixdecoder.WithDictionary(dictionary))
+	fields := decoder.Decode("8=FIX.4.4|9=0|35=D|11=ORD1|453=1|448=BRKR|452=1|54=2|5001=Y|10=000|")
+
+	venueFlag := fields[8]
+	if venueFlag.Field.Name != "VenueOrderFlag" || venueFlag.DecodedValue != "FLAGGED" {
+		t.Errorf("expect VenueOrderFlag FLAGGED, actual %s %s", venueFlag.Field.Name, venueFlag.DecodedValue)
+	}
+
+	if fields[4].Group == nil || len(fields[4].Group.Instances) != 1 {
+		t.Errorf("expect 1 instance of NoPartyIDs")
+	}
+
+	v := &fixdecoder.MessageValidator{Dictionary: dictionary}
+	if issues := v.Validate(fields); len(issues) != 0 {
+		t.Errorf("expect valid, actual %v", issues)
+	}
+}
+
+func TestLoadQuickFIXDictionary_UnknownField(t *testing.T) {
+	broken := strings.Replace(quickfixdictionary, `<field name="Side" required="Y"/>`, `<field name="Sidee" required="Y"/>`, 1)
+	if _, err := fixdecoder.LoadQuickFIXDictionary(strings.NewReader(broken)); err == nil {
+		t.Error("expect unknown field error")
+	}
+}
+
+func TestFixDecoder_VersionDictionaries(t *testing.T) {
+	fields := fd.Decode("8=FIX.4.2|9=0|35=8|20=0|660=1|10=000|")
+	if actual := fields[3].Field.Name; actual != "ExecTransType" {
+		t.Errorf("expect ExecTransType, actual %s", actual)
+	}
+
+	if actual := fields[4].Field.Name; actual != "" {
+		t.Errorf("expect AcctIDSource unknown in FIX.4.2, actual %s", actual)
+	}
+
+	msg, err := fd.DecodeE("8=FIXT.1.1|9=0|35=W|1128=9|1300=SEG|1003=T1|10=000|")
+	if err != nil {
+		t.Fatalf("expect no error, actual %v", err)
+	}
+
+	if msg.Dictionary.Version != "FIX.5.0SP2" {
+		t.Errorf("expect FIX.5.0SP2, actual %s", msg.Dictionary.Version)
+	}
+
+	if expect, actual := "ApplVerID,MarketSegmentID,TradeID", msg.Fields[3].Field.Name+","+msg.Fields[4].Field.Name+","+msg.Fields[5].Field.Name; actual != expect {
+		t.Errorf("expect %s, actual %s", expect, actual)
+	}
+
+	decoder := fixdecoder.NewFixDecoder(fixdecoder.WithDefaultApplVerID("7"))
+	fields = decoder.Decode("8=FIXT.1.1|9=0|35=W|1300=SEG|1003=T1|10=000|")
+	if fields[3].Field.Name != "" || fields[4].Field.Name != "TradeID" {
+		t.Errorf("expect FIX.5.0 fields only, actual %s %s", fields[3].Field.Name, fields[4].Field.Name)
+	}
+
+	v := &fixdecoder.MessageValidator{}
+	issues := v.Validate(fd.Decode("8=FIXT.1.1|9=0|35=A|49=A|56=B|34=1|52=20180126-07:39:59.683|98=0|108=30|10=000|"))
+	if expect, actual := "1137", issueTags(issues, fixdecoder.MISSINGFIELD); actual != expect {
+		t.Errorf("expect missing %s, actual %s", expect, actual)
+	}
+}
+
+func TestVersionDictionary_Copies(t *testing.T) {
+	fix42, fix50 := fixdecoder.VersionDictionary("FIX.4.2"), fixdecoder.VersionDictionary("FIX.5.0")
+	if fix42.Field("54") == fixdecoder.DefaultDictionary().Field("54") || fix50.Field("1128") == fixdecoder.VersionDictionary("FIXT.1.1").Field("1128") {
+		t.Error("expect the field definitions of each version to be copies")
+	}
+
+	fix42.Field("54").Values["Z"] = "House side"
+	defer delete(fix42.Field("54").Values, "Z")
+	if _, found := fixdecoder.DefaultDictionary().Field("54").Values["Z"]; found {
+		t.Error("expect FIX.4.4 Side values left untouched")
+	}
+}
+
+func TestLoadQuickFIXVersionDictionaries(t *testing.T) {
+	dir := t.TempDir()
+	spec := strings.Replace(quickfixdictionary, `minor="4"`, `minor="9"`, 1)
+	if err := os.WriteFile(filepath.Join(dir, "FIX49.xml"), []byte(spec), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	versions, err := fixdecoder.LoadQuickFIXVersionDictionaries(dir)
+	if err != nil || len(versions) != 1 || versions[0] != "FIX.4.9" {
+		t.Fatalf("expect FIX.4.9, actual %v %v", versions, err)
+	}
+
+	fields := fd.Decode("8=FIX.4.9|9=0|35=D|11=ORD1|54=2|5001=Y|10=000|")
+	if actual := fields[5].Field.Name; actual != "VenueOrderFlag" {
+		t.Errorf("expect VenueOrderFlag, actual %s", actual)
+	}
+
+	if _, err := fixdecoder.LoadQuickFIXVersionDictionaries(t.TempDir()); err == nil {
+		t.Error("expect no dictionary error")
+	}
+}
+
+func TestFixDecoder_VersionDictionary_DataField(t *testing.T) {
+	spec := strings.Replace(quickfixdictionary, `minor="4"`, `minor="9"`, 1)
+	spec = strings.Replace(spec, ` </fields>`, `  <field number="6000" name="VenueDataLen" type="LENGTH"/>
+  <field number="6001" name="VenueData" type="DATA"/>
+ </fields>`, 1)
+	dictionary, err := fixdecoder.LoadQuickFIXDictionary(strings.NewReader(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoder := fixdecoder.NewFixDecoder(fixdecoder.WithVersionDictionary("FIX.4.9", dictionary))
+	message := "8=FIX.4.9|9=0|35=D|6000=3|6001=a|b|10=000|"
+	if expect, actual := "a|b", decoder.Decode(message)[4].Value; actual != expect {
+		t.Errorf("expect %s, actual %s", expect, actual)
+	}
+
+	msg, err := decoder.DecodeE(message)
+	if err != nil || msg.Fields[4].Value != "a|b" {
+		t.Errorf("expect a|b, actual %v %v", msg, err)
+	}
+}
+
+func TestDictionary_JSON(t *testing.T) {
+	d, _ := fixdecoder.LoadQuickFIXDictionary(strings.NewReader(quickfixdictionary))
+	data, err := d.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := fixdecoder.LoadJSONDictionary(string(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if field := loaded.Field("5001"); field == nil || field.Name != "VenueOrderFlag" {
+		t.Errorf("expect VenueOrderFlag, actual %v", field)
+	}
+
+	if expect, actual := len(d.Messages), len(loaded.Messages); actual != expect {
+		t.Errorf("expect %d, actual %d", expect, actual)
+	}
+
+	if expect, actual := strings.Join(d.Header.Required, ","), strings.Join(loaded.Header.Required, ","); actual != expect {
+		t.Errorf("expect %s, actual %s", expect, actual)
+	}
+}
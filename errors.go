@@ -0,0 +1,104 @@
+package fixdecoder
+
+import "fmt"
+
+// ErrNoBeginString the message does not start with BeginString <8>
+type ErrNoBeginString struct {
+	Offset int
+	Raw    string
+}
+
+func (e *ErrNoBeginString) Error() string {
+	return fmt.Sprintf("fixdecoder: expected BeginString (8) at offset %d, found %q", e.Offset, e.Raw)
+}
+
+// ErrMalformedField a field is not in the {{tag}}={{value}} format, or its tag is not a number
+type ErrMalformedField struct {
+	Offset int
+	Raw    string
+}
+
+func (e *ErrMalformedField) Error() string {
+	return fmt.Sprintf("fixdecoder: malformed field at offset %d: %q", e.Offset, e.Raw)
+}
+
+// ErrTruncated the message ended before the CheckSum <10> field and its trailing delimiter
+type ErrTruncated struct {
+	Offset int
+}
+
+func (e *ErrTruncated) Error() string {
+	return fmt.Sprintf("fixdecoder: message truncated at offset %d", e.Offset)
+}
+
+// ErrGarbageBetweenFields bytes that do not belong to any field were found between two fields, or after CheckSum <10>
+type ErrGarbageBetweenFields struct {
+	Offset int
+	Raw    string
+}
+
+func (e *ErrGarbageBetweenFields) Error() string {
+	return fmt.Sprintf("fixdecoder: unexpected data at offset %d: %q", e.Offset, e.Raw)
+}
+
+// ErrSeqNumMismatch the MsgSeqNum <34> of a stored message is not the sequence number the store indexes it by
+type ErrSeqNumMismatch struct {
+	Offset   int
+	Expected int    // Sequence number of the index
+	Actual   string // MsgSeqNum <34> of the message, empty if it has none
+}
+
+func (e *ErrSeqNumMismatch) Error() string {
+	return fmt.Sprintf("fixdecoder: message at offset %d indexed as %d has MsgSeqNum %q", e.Offset, e.Expected, e.Actual)
+}
+
+// withOffset shift the offset of a decode error by base, e.g. to make it relative to a stream instead of a message
+func withOffset(err error, base int) error {
+	switch e := err.(type) {
+	case *ErrNoBeginString:
+		e.Offset += base
+	case *ErrMalformedField:
+		e.Offset += base
+	case *ErrTruncated:
+		e.Offset += base
+	case *ErrGarbageBetweenFields:
+		e.Offset += base
+	}
+
+	return err
+}
+
+// ErrFieldNotFound the message has no field with this tag
+type ErrFieldNotFound struct {
+	Tag int
+}
+
+func (e *ErrFieldNotFound) Error() string {
+	return fmt.Sprintf("fixdecoder: field %d not found", e.Tag)
+}
+
+// ErrInvalidValue the value of a field does not conform to the type it was read as
+type ErrInvalidValue struct {
+	Tag       int
+	Type      string // The type the value was read as, e.g. INT for DecodedField.Int
+	FieldType string // The declared type of the field in the dictionary, e.g. STRING, empty if unknown
+	Value     string
+}
+
+func (e *ErrInvalidValue) Error() string {
+	if e.FieldType != "" && e.FieldType != e.Type {
+		return fmt.Sprintf("fixdecoder: invalid %s value %q for %s field %d", e.Type, e.Value, e.FieldType, e.Tag)
+	}
+
+	return fmt.Sprintf("fixdecoder: invalid %s value %q for field %d", e.Type, e.Value, e.Tag)
+}
+
+// ErrAmbiguousField the message has more than one field with this tag, and the lookup policy is LOOKUPERROR
+type ErrAmbiguousField struct {
+	Tag   int
+	Count int
+}
+
+func (e *ErrAmbiguousField) Error() string {
+	return fmt.Sprintf("fixdecoder: field %d appears %d times", e.Tag, e.Count)
+}
@@ -0,0 +1,138 @@
+package fixdecoder
+
+import (
+	"regexp"
+	"strings"
+)
+
+// jsonSOH SOH escaped in a JSON string, e.g. in JSON log envelopes
+const jsonSOH = `\u0001`
+
+// Message directions found in log prefixes
+const (
+	INBOUND  = "IN"
+	OUTBOUND = "OUT"
+)
+
+var (
+	// prefixTimestamp FIX (20260301-10:00:00.123), ISO 8601 (2026-03-01T10:00:00.123Z) or Go log (2026/03/01 10:00:00.123456) timestamps
+	prefixTimestamp = regexp.MustCompile(`\d{8}-\d{2}:\d{2}:\d{2}(\.\d+)?|\d{4}[-/]\d{2}[-/]\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`)
+	// prefixInbound direction markers of received messages
+	prefixInbound = regexp.MustCompile(`(?i)\b(in|incoming|inbound|received|recv|rcvd)\b|<<`)
+	// prefixOutbound direction markers of sent messages
+	prefixOutbound = regexp.MustCompile(`(?i)\b(out|outgoing|outbound|sent|send|sending)\b|>>`)
+	// prefixSessionID QuickFIX session IDs, e.g. FIX.4.4:CLIENT->BROKER
+	prefixSessionID = regexp.MustCompile(`FIXT?\.\d\.\d(SP\d)?:[^\s:>]+->[^\s,;"'\]\)]+`)
+)
+
+// ExtractedMessage a FIX message found in a log line, with what the text before it tells about it
+type ExtractedMessage struct {
+	Raw       string // The message, from 8=FIX to the delimiter after CheckSum <10>. JSON escaped SOH are unescaped
+	Offset    int    // Byte offset of the message within the line
+	Prefix    string // Text between the previous message, or the start of the line, and the message
+	Timestamp string // Timestamp found in the prefix, if any
+	Direction string // INBOUND or OUTBOUND if the prefix tells, e.g. with "incoming" or "<<"
+	SessionID string // QuickFIX session ID found in the prefix, e.g. FIX.4.4:CLIENT->BROKER
+	Fields    DecodedFields
+}
+
+// Extractor extract and decode the FIX messages embedded in log lines, e.g. "20260301-10:00:00.123 : 8=FIX.4.4|9=...|10=123|"
+// or JSON log envelopes. Only the messages themselves are decoded, never the text around them
+type Extractor struct {
+	decoder *FixDecoder
+}
+
+// NewExtractor new extractor instance. Options are the ones of NewFixDecoder
+func NewExtractor(options ...Option) *Extractor {
+	return &Extractor{decoder: NewFixDecoder(options...)}
+}
+
+// Extract extract and decode the FIX messages of a line. nil if there is none
+func (e *Extractor) Extract(line string) []*ExtractedMessage {
+	var messages []*ExtractedMessage
+
+	for pos := 0; pos < len(line); {
+		start := strings.Index(line[pos:], string(beginStringPrefix))
+		if start < 0 {
+			break
+		}
+		start += pos
+
+		end, delimiter := e.span(line, start)
+		raw := line[start:end]
+		if delimiter == jsonSOH {
+			raw = strings.Replace(raw, jsonSOH, SOH, -1)
+		}
+
+		prefix := line[pos:start]
+		messages = append(messages, &ExtractedMessage{
+			Raw:       raw,
+			Offset:    start,
+			Prefix:    prefix,
+			Timestamp: prefixTimestamp.FindString(prefix),
+			Direction: direction(prefix),
+			SessionID: prefixSessionID.FindString(prefix),
+			Fields:    e.decoder.Decode(raw),
+		})
+
+		pos = end
+	}
+
+	return messages
+}
+
+// span the end of the message starting at line[start], and its delimiter. The message ends after the delimiter following CheckSum <10>,
+// or after the CheckSum value if no delimiter follows it, e.g. before the closing quote of a JSON string; otherwise before the next message
+func (e *Extractor) span(line string, start int) (int, string) {
+	delimiter := e.decoder.delimiter
+	if delimiter == "" {
+		p := start + len("8=")
+		for p < len(line) && isBeginStringByte(line[p]) {
+			p++
+		}
+
+		if strings.HasPrefix(line[p:], jsonSOH) {
+			delimiter = jsonSOH
+		} else {
+			delimiter = DetectDelimiter([]byte(line[start:]))
+		}
+	}
+
+	limit := len(line)
+	if next := strings.Index(line[start+1:], string(beginStringPrefix)); next >= 0 {
+		limit = start + 1 + next
+	}
+
+	if delimiter == "" {
+		return limit, delimiter
+	}
+
+	checksum := strings.Index(line[start:limit], delimiter+CHECKSUM+"=")
+	if checksum < 0 {
+		return limit, delimiter
+	}
+
+	end := start + checksum + len(delimiter) + len(CHECKSUM+"=")
+	for end < limit && isDigit(line[end]) {
+		end++
+	}
+
+	if strings.HasPrefix(line[end:limit], delimiter) {
+		end += len(delimiter)
+	}
+
+	return end, delimiter
+}
+
+// direction INBOUND or OUTBOUND according to the markers of a prefix, empty if it has none or both
+func direction(prefix string) string {
+	inbound, outbound := prefixInbound.MatchString(prefix), prefixOutbound.MatchString(prefix)
+	switch {
+	case inbound && !outbound:
+		return INBOUND
+	case outbound && !inbound:
+		return OUTBOUND
+	}
+
+	return ""
+}
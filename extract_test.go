@@ -0,0 +1,45 @@
+package fixdecoder_test
+
+import (
+	"testing"
+
+	fixdecoder "github.com/ilovelili/FixDecoder"
+)
+
+func TestExtractor(t *testing.T) {
+	message := "8=FIX.4.4|9=74|35=2|49=CNX|34=8263336|52=20180126-07:39:59.683|56=imdstream|16=0|7=12812|10=036|"
+	extractor := fixdecoder.NewExtractor()
+
+	messages := extractor.Extract("20260301-10:00:00.123 FIX.4.4:CNX->imdstream incoming: " + message + " (resend)")
+	if len(messages) != 1 {
+		t.Fatalf("expect 1 message, actual %d", len(messages))
+	}
+
+	m := messages[0]
+	if m.Raw != message || m.Timestamp != "20260301-10:00:00.123" || m.Direction != fixdecoder.INBOUND || m.SessionID != "FIX.4.4:CNX->imdstream" {
+		t.Errorf("expect message with its prefix metadata, actual %+v", m)
+	}
+
+	if expect, actual := 10, len(m.Fields); actual != expect || m.Fields[0].FieldID != "8" {
+		t.Errorf("expect %d fields from BeginString, actual %d", expect, actual)
+	}
+
+	// JSON envelope with escaped SOH, two messages
+	escaped := `8=FIX.4.4\u00019=5\u000135=0\u000110=161\u0001`
+	messages = extractor.Extract(`{"ts":"2026-03-01T10:00:00.123Z","dir":"out","msg":"` + escaped + `"} {"dir":"in","msg":"` + escaped + `"}`)
+	if len(messages) != 2 {
+		t.Fatalf("expect 2 messages, actual %d", len(messages))
+	}
+
+	if m := messages[0]; m.Raw != "8=FIX.4.4\x019=5\x0135=0\x0110=161\x01" || m.Timestamp != "2026-03-01T10:00:00.123Z" || m.Direction != fixdecoder.OUTBOUND {
+		t.Errorf("expect outbound heartbeat, actual %+v", m)
+	}
+
+	if m := messages[1]; m.Direction != fixdecoder.INBOUND || len(m.Fields) != 4 || m.Fields[3].DecodedValue != "" {
+		t.Errorf("expect inbound heartbeat, actual %+v", m)
+	}
+
+	if messages := extractor.Extract("20260301-10:00:00.123 session started"); messages != nil {
+		t.Errorf("expect no message, actual %v", messages)
+	}
+}
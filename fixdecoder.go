@@ -3,10 +3,7 @@ package fixdecoder
 
 import (
 	"encoding/json"
-	"regexp"
 	"strings"
-
-	"github.com/tidwall/gjson"
 )
 
 const (
@@ -16,9 +13,11 @@ const (
 	BEGINSTRING = "8"
 	// BODYLENGTH Message length, in bytes, forward to the CheckSum <10> field. ALWAYS SECOND FIELD IN MESSAGE. (Always unencrypted)
 	BODYLENGTH = "9"
+	// MSGTYPE Defines message type. ALWAYS THIRD FIELD IN MESSAGE. (Always unencrypted)
+	MSGTYPE = "35"
 )
 
-// FieldMetaData meta data of a field
+// FieldMetaData meta data of a field. It is shared by all the fields decoded with the same definition
 type FieldMetaData struct {
 	Name string
 	Type string
@@ -27,30 +26,35 @@ type FieldMetaData struct {
 // DecodedField decoded field
 type DecodedField struct {
 	FieldID      string
+	Tag          int // FieldID as a number, -1 if it does not fit
 	Value        string
 	Field        *FieldMetaData
 	DecodedValue string
 	Classes      string
-	Decoded      bool // Whether decoding succeeded or not
+	Decoded      bool          // Whether decoding succeeded or not
+	Offset       int           // Byte offset of the field within the message
+	Group        *DecodedGroup // Repeating group introduced by this field, if it is a NUMINGROUP field
 }
 
 // DecodedFields alias of DecodedField slice
 type DecodedFields []*DecodedField
 
-// Raw parse the raw message
+// Bytes the value as bytes, e.g. the binary payload of a DATA field
+func (df *DecodedField) Bytes() []byte {
+	return []byte(df.Value)
+}
+
+// Raw the field as sent on the wire. The delimiter is always SOH, whatever the delimiter of the decoded message (e.g. PIPE or CARETSOH in logs),
+// so that BodyLength and CheckSum are computed as on the wire
 func (df *DecodedField) Raw() string {
 	// "\x01" stands for ascii SOH, which is used as a delimiter in FIX protocol
 	return df.FieldID + "=" + df.Value + "\x01"
 }
 
-// String decode to string
+// String decode to string. Validation issues are shown as decoded values, e.g. "Invalid (expected 036)"; the fields are left untouched
 func (dfs DecodedFields) String() string {
 	result := make([]string, 0)
-
-	validators := NewValidatorFactory().CreateValidators()
-	for _, v := range validators {
-		v.Validate(dfs)
-	}
+	issues := dfs.Validate()
 
 	for _, line := range dfs {
 		if !line.Decoded {
@@ -58,7 +62,7 @@ func (dfs DecodedFields) String() string {
 		}
 
 		var output interface{}
-		if line.DecodedValue != "" {
+		if decodedValue := line.Describe(issues); decodedValue != "" {
 			output = struct {
 				ID           string
 				Name         string
@@ -68,7 +72,7 @@ func (dfs DecodedFields) String() string {
 				ID:           line.FieldID,
 				Name:         line.Field.Name,
 				Value:        line.Value,
-				DecodedValue: line.DecodedValue,
+				DecodedValue: decodedValue,
 			}
 		} else {
 			output = struct {
@@ -90,83 +94,286 @@ func (dfs DecodedFields) String() string {
 }
 
 // FixDecoder the main struct
-type FixDecoder struct{}
+type FixDecoder struct {
+	dictionary       *Dictionary            // Used for all messages if set
+	dictionaries     map[string]*Dictionary // Used instead of the built-in dictionaries, group by version
+	defaultApplVerID string
+	delimiter        string // Set by WithDelimiter, empty to detect it from every message
+	profile          *ValidationProfile
+	lookup           string // Lookup policy of the decoded messages, see WithLookupPolicy
+}
+
+// Option fix decoder option
+type Option func(*FixDecoder)
+
+// WithDictionary decode all messages with the given dictionary, e.g. one loaded by LoadQuickFIXDictionary, whatever their version
+func WithDictionary(dictionary *Dictionary) Option {
+	return func(f *FixDecoder) {
+		f.dictionary = dictionary
+	}
+}
+
+// WithVersionDictionary decode the messages of a version (e.g. FIX.4.2, FIX.5.0SP2 or FIXT.1.1) with the given dictionary instead of the built-in one
+func WithVersionDictionary(version string, dictionary *Dictionary) Option {
+	return func(f *FixDecoder) {
+		f.dictionaries[version] = dictionary
+	}
+}
+
+// WithDefaultApplVerID the DefaultApplVerID <1137> of the session, used for FIXT.1.1 messages without ApplVerID <1128>
+func WithDefaultApplVerID(applVerID string) Option {
+	return func(f *FixDecoder) {
+		f.defaultApplVerID = applVerID
+	}
+}
+
+// WithValidationProfile validate messages with the rules of a profile, e.g. the one of the counterparty
+func WithValidationProfile(profile *ValidationProfile) Option {
+	return func(f *FixDecoder) {
+		f.profile = profile
+	}
+}
+
+// WithLookupPolicy how the lookups by tag of decoded messages (Message.Lookup, GetInt...) deal with a tag appearing more than once:
+// LOOKUPFIRST (the default) or LOOKUPLAST return that occurrence, LOOKUPERROR returns an ErrAmbiguousField error
+func WithLookupPolicy(policy string) Option {
+	return func(f *FixDecoder) {
+		f.lookup = policy
+	}
+}
+
+// NewFixDecoder new fix decoder instance. By default the dictionary is picked per message from its BeginString <8>,
+// and from its ApplVerID <1128> for FIXT.1.1 messages
+func NewFixDecoder(options ...Option) *FixDecoder {
+	f := &FixDecoder{dictionaries: make(map[string]*Dictionary), defaultApplVerID: "9"}
+	for _, option := range options {
+		option(f)
+	}
+
+	return f
+}
+
+// Dictionary get the dictionary set by WithDictionary, or the built-in FIX 4.4 one
+func (f *FixDecoder) Dictionary() *Dictionary {
+	if f.dictionary != nil {
+		return f.dictionary
+	}
+
+	return DefaultDictionary()
+}
+
+// Validate run the validators enabled by the profile of the decoder, or by default without one. Dictionary validators
+// (e.g. MessageValidator) check the message against the dictionary the decoder picks for it, see DictionaryValidator
+func (f *FixDecoder) Validate(dfs DecodedFields) []*ValidationIssue {
+	return f.profile.validate(dfs, f.SelectDictionary(fieldValue(dfs, BEGINSTRING), fieldValue(dfs, APPLVERID)))
+}
+
+// SelectDictionary pick the dictionary of a message from its BeginString <8> and ApplVerID <1128>
+func (f *FixDecoder) SelectDictionary(beginString, applVerID string) *Dictionary {
+	if f.dictionary != nil {
+		return f.dictionary
+	}
+
+	return selectDictionary(f.versionDictionary, beginString, applVerID, f.defaultApplVerID)
+}
+
+// versionDictionary the dictionary of a version, the built-in one unless set by WithVersionDictionary
+func (f *FixDecoder) versionDictionary(version string) *Dictionary {
+	if d, found := f.dictionaries[version]; found {
+		return d
+	}
 
-// NewFixDecoder new fix decoder instance
-func NewFixDecoder() *FixDecoder {
-	return &FixDecoder{}
+	return VersionDictionary(version)
 }
 
 // parseVersionFromBeginString get the FIX protocol version
 func (f *FixDecoder) parseVersionFromBeginString(beginStr string) string {
-	return beginStr[len("FIX."):]
+	if i := strings.IndexByte(beginStr, '.'); i >= 0 {
+		return beginStr[i+1:]
+	}
+
+	return beginStr
+}
+
+// rawField a {{fieldId}}={{value}} pair found in a message, not decoded yet
+type rawField struct {
+	fieldID string
+	tag     int
+	value   string
+	offset  int
+}
+
+// maxStackFields messages with up to this many fields are scanned without allocating
+const maxStackFields = 64
+
+// scan scan the fields of a message with a scanner set up by the decoder. Field ids and values are sub-strings of the message.
+// DATA fields are framed with the dictionary the decoder picks for the message, switched to as soon as BeginString <8> and ApplVerID <1128> are read
+func (f *FixDecoder) scan(s *Scanner, message string, raws []rawField) []rawField {
+	beginString, applVerID := "", ""
+	s.SetDictionary(f.SelectDictionary(beginString, applVerID))
+	s.SetDelimiter(f.delimiter)
+	s.Reset([]byte(message))
+	for s.Next() {
+		raw := rawField{fieldID: message[s.offset:s.eq], tag: s.tag, value: message[s.eq+1 : s.eq+1+len(s.value)], offset: s.offset}
+		raws = append(raws, raw)
+
+		switch {
+		case raw.fieldID == BEGINSTRING && beginString == "":
+			beginString = raw.value
+			s.SetDictionary(f.SelectDictionary(beginString, applVerID))
+		case raw.fieldID == APPLVERID && applVerID == "":
+			applVerID = raw.value
+			s.SetDictionary(f.SelectDictionary(beginString, applVerID))
+		}
+	}
+
+	return raws
 }
 
-// Decode the main decode function
+// Decode the main decode function. Anything that is not a {{fieldId}}={{value}} field is skipped, see DecodeE for a strict decoding
 func (f *FixDecoder) Decode(message string) (decodedfields DecodedFields) {
-	regex := regexp.MustCompile("([0-9]+)=([^|;\x01]*)")
-	decodedfields = make([]*DecodedField, 0)
+	var buffer [maxStackFields]rawField
+	s := Scanner{lenient: true}
+	decodedfields, _, _ = f.decodeFields(f.scan(&s, message, buffer[:0]))
+	return decodedfields
+}
+
+// DecodeE decode a single FIX message strictly. Unlike Decode, nothing is skipped: the message must start with BeginString <8>,
+// every field must be {{fieldId}}={{value}} followed by a delimiter, and the message must end with CheckSum <10>.
+// The returned error is one of *ErrNoBeginString, *ErrMalformedField, *ErrTruncated or *ErrGarbageBetweenFields
+func (f *FixDecoder) DecodeE(message string) (*Message, error) {
+	var buffer [maxStackFields]rawField
+	var s Scanner
+	raws := f.scan(&s, message, buffer[:0])
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	msg := &Message{BeginString: raws[0].value, Raw: message, Delimiter: s.Delimiter(), lookup: f.lookup}
+	msg.Fields, msg.Groups, msg.Dictionary = f.decodeFields(raws)
+	return msg, nil
+}
+
+// decodeFields pick the dictionary of the message, decode its fields and link its repeating groups. It returns the top level groups.
+// The decoded fields are allocated at once
+func (f *FixDecoder) decodeFields(raws []rawField) (DecodedFields, []*DecodedGroup, *Dictionary) {
+	beginString, applVerID := "", ""
+	for _, raw := range raws {
+		if raw.fieldID == BEGINSTRING && beginString == "" {
+			beginString = raw.value
+		} else if raw.fieldID == APPLVERID && applVerID == "" {
+			applVerID = raw.value
+		}
+	}
+
+	dictionary := f.SelectDictionary(beginString, applVerID)
 
 	fixVersion := "unknown"
-	fields := Fields()
-	systemFieldIDs := SystemFieldIDs()
-
-	for i, result := 0, regex.FindAllString(message, -1); i < len(result); i++ {
-		// {{fieldId}}={{value}}
-		if parsed := regex.FindStringSubmatch(result[i]); len(parsed) == 3 {
-			fieldID := parsed[1]
-			value := parsed[2]
-			field := gjson.Get(fields, fieldID)
-			decodedValue := ""
-
-			if found, values := hasField(field, "values"); found {
-				decodedValue = gjson.Get(values, value).String()
-			}
+	if dictionary.Version != "" {
+		fixVersion = f.parseVersionFromBeginString(dictionary.Version)
+	} else if beginString != "" {
+		fixVersion = f.parseVersionFromBeginString(beginString)
+	}
 
-			if BEGINSTRING == fieldID {
-				fixVersion = f.parseVersionFromBeginString(value)
-			}
+	fields := make([]DecodedField, len(raws))
+	decodedfields := make(DecodedFields, len(raws))
+	for i, raw := range raws {
+		decodeField(dictionary, fixVersion, raw, &fields[i])
+		decodedfields[i] = &fields[i]
+	}
 
-			classes := make([]string, 0)
+	return decodedfields, buildGroups(dictionary, decodedfields), dictionary
+}
 
-			if _, contain := contains(systemFieldIDs, fieldID); contain {
-				classes = append(classes, "system-field")
-			}
+// unknownField the definition of the fields missing from the dictionary
+var unknownField = &FieldDefinition{metaData: &FieldMetaData{}}
 
-			if found, _ := hasField(field, "isRequired"); found {
-				classes = append(classes, "required-field")
-			}
+// Classes of decoded fields
+const (
+	systemClass = 1 << iota
+	requiredClass
+	headerClass
+	deprecatedClass
+)
 
-			if found, _ := hasField(field, "isHeaderField"); found {
-				classes = append(classes, "header-field")
+// fieldClasses the classes of a decoded field, by combination of systemClass, requiredClass, headerClass and deprecatedClass
+var fieldClasses = func() (result [16]string) {
+	names := []string{"system-field", "required-field", "header-field", "deprecated-field"}
+	for combination := range result {
+		classes := make([]string, 0, len(names))
+		for i, name := range names {
+			if combination&(1<<i) != 0 {
+				classes = append(classes, name)
 			}
+		}
+		result[combination] = strings.Join(classes, ",")
+	}
 
-			if found, value := hasField(field, "deprecatedSince"); found {
-				if value <= fixVersion {
-					classes = append(classes, "deprecated-field")
-				}
-			}
+	return
+}()
 
-			decodedfields = append(decodedfields, &DecodedField{
-				FieldID: fieldID,
-				Value:   value,
-				Field: &FieldMetaData{
-					Name: gjson.Get(field.String(), "name").String(),
-					Type: gjson.Get(field.String(), "type").String(),
-				},
-				Classes:      strings.Join(classes, ","),
-				DecodedValue: decodedValue,
-				Decoded:      true,
-			})
-		} else {
-			// parsing failed
-			decodedfields = append(decodedfields, &DecodedField{
-				Decoded: false,
-			})
+// decodeField look up the field in the dictionary and decode its value into df
+func decodeField(dictionary *Dictionary, fixVersion string, raw rawField, df *DecodedField) {
+	field := dictionary.FieldByTag(raw.tag)
+	if field == nil {
+		field = unknownField
+	}
+
+	classes := 0
+	if _, contain := contains(dictionary.SystemFieldIDs, raw.fieldID); contain {
+		classes |= systemClass
+	}
+
+	if field.IsRequired {
+		classes |= requiredClass
+	}
+
+	if field.IsHeaderField {
+		classes |= headerClass
+	}
+
+	if field.DeprecatedSince != "" && field.DeprecatedSince <= fixVersion {
+		classes |= deprecatedClass
+	}
+
+	*df = DecodedField{
+		FieldID:      raw.fieldID,
+		Tag:          raw.tag,
+		Value:        raw.value,
+		Field:        field.metaData,
+		Classes:      fieldClasses[classes],
+		DecodedValue: decodeValue(field, raw.value),
+		Decoded:      true,
+		Offset:       raw.offset,
+	}
+}
+
+// decodeValue the description of an enumerated value, empty if unknown. The space separated values of MULTIPLEVALUESTRING fields
+// are decoded one by one, e.g. "Not Held, No Cross, All Or None" for ExecInst <18> "1 A G"; unknown ones are kept as is
+func decodeValue(field *FieldDefinition, value string) string {
+	if !isMultipleValue(field.Type) || strings.IndexByte(value, ' ') < 0 {
+		return field.Values[value]
+	}
+
+	known := false
+	descriptions := strings.Fields(value)
+	for i, token := range descriptions {
+		if description, found := field.Values[token]; found {
+			descriptions[i] = description
+			known = true
 		}
 	}
 
-	return DecodedFields(decodedfields)
+	if !known {
+		return ""
+	}
+
+	return strings.Join(descriptions, ", ")
+}
+
+// isMultipleValue whether a field of this type holds space separated values
+func isMultipleValue(fieldType string) bool {
+	return fieldType == "MULTIPLEVALUESTRING" || fieldType == "MULTIPLESTRINGVALUE" || fieldType == "MULTIPLECHARVALUE"
 }
 
 // Array.contains
@@ -183,15 +390,3 @@ func contains(source []string, target string) (index int, contains bool) {
 
 	return
 }
-
-// help method to wrap gjson exists
-func hasField(g gjson.Result, field string) (found bool, value string) {
-	value = ""
-	searchField := gjson.Get(g.String(), field)
-	found = searchField.Exists()
-	if found {
-		value = searchField.String()
-	}
-
-	return
-}
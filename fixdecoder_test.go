@@ -1,6 +1,8 @@
 package fixdecoder_test
 
 import (
+	"errors"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -53,3 +55,464 @@ func TestFixDecoder_Invalid_BodyLength(t *testing.T) {
 		t.Errorf("expect %s, actual %s", expect, actual)
 	}
 }
+
+func TestFixDecoder_Missing_BodyLength_CheckSum(t *testing.T) {
+	issues := fixdecoder.BodyLengthValidator{}.Validate(fd.Decode("8=FIX.4.4|35=0|49=A|56=B|10=000|"))
+	if len(issues) != 1 || issues[0].Tag != 9 || issues[0].Code != fixdecoder.MISSINGFIELD {
+		t.Errorf("expect missing BodyLength, actual %v", issues)
+	}
+
+	issues = fixdecoder.CheckSumValidator{}.Validate(fd.Decode("8=FIX.4.4|9=20|35=0|49=A|56=B|"))
+	if len(issues) != 1 || issues[0].Tag != 10 || issues[0].Code != fixdecoder.MISSINGFIELD {
+		t.Errorf("expect missing CheckSum, actual %v", issues)
+	}
+}
+
+func TestFixDecoder_DecodeE_Valid(t *testing.T) {
+	msg, err := fd.DecodeE(validfixmessage)
+	if err != nil {
+		t.Fatalf("expect no error, actual %v", err)
+	}
+
+	if msg.BeginString != "FIX.4.4" || len(msg.Fields) != 10 {
+		t.Errorf("expect FIX.4.4 with 10 fields, actual %s with %d fields", msg.BeginString, len(msg.Fields))
+	}
+
+	if actual := msg.Fields[2].Offset; actual != 15 {
+		t.Errorf("expect MsgType at offset 15, actual %d", actual)
+	}
+}
+
+func TestFixDecoder_DecodeE_Errors(t *testing.T) {
+	var (
+		noBeginString *fixdecoder.ErrNoBeginString
+		malformed     *fixdecoder.ErrMalformedField
+		truncated     *fixdecoder.ErrTruncated
+		garbage       *fixdecoder.ErrGarbageBetweenFields
+	)
+
+	cases := []struct {
+		message string
+		target  interface{}
+		offset  func() int
+		expect  int
+	}{
+		{invalidfixmessage_badformat, &noBeginString, func() int { return noBeginString.Offset }, 0},
+		{"9=74\x018=FIX.4.4\x01", &noBeginString, func() int { return noBeginString.Offset }, 0},
+		{"8=FIX.4.4\x019=5\x01x5=0\x0110=000\x01", &malformed, func() int { return malformed.Offset }, 14},
+		{"8=FIX.4.4\x019=5\x0135=0\x01", &truncated, func() int { return truncated.Offset }, 19},
+		{"8=FIX.4.4\x019=5\x0135=0\x0110=000", &truncated, func() int { return truncated.Offset }, 25},
+		{"8=FIX.4.4\x019=5\x01junk\x0135=0\x0110=000\x01", &garbage, func() int { return garbage.Offset }, 14},
+		{"8=FIX.4.4\x019=5\x0135=0\x0110=000\x01junk", &garbage, func() int { return garbage.Offset }, 26},
+	}
+
+	for _, c := range cases {
+		_, err := fd.DecodeE(c.message)
+		if !errors.As(err, c.target) {
+			t.Errorf("%q: expect %T, actual %v", c.message, c.target, err)
+			continue
+		}
+
+		if actual := c.offset(); actual != c.expect {
+			t.Errorf("%q: expect offset %d, actual %d", c.message, c.expect, actual)
+		}
+	}
+}
+
+func TestFixDecoder_DecodeE_Groups(t *testing.T) {
+	message := "8=FIX.4.4|9=0|35=D|11=ORD1|453=2|448=BRKR|447=D|452=1|802=1|523=DESK|803=4|448=CLNT|447=D|452=3|55=IBM|54=1|10=000|"
+	msg, err := fd.DecodeE(message)
+	if err != nil {
+		t.Fatalf("expect no error, actual %v", err)
+	}
+
+	if len(msg.Groups) != 1 {
+		t.Fatalf("expect 1 group, actual %d", len(msg.Groups))
+	}
+
+	parties := msg.Groups[0]
+	if parties.CountField.FieldID != "453" || len(parties.Instances) != 2 {
+		t.Fatalf("expect 2 instances of 453, actual %d instances of %s", len(parties.Instances), parties.CountField.FieldID)
+	}
+
+	if actual := len(parties.Instances[0]); actual != 4 {
+		t.Errorf("expect 4 fields in first instance, actual %d", actual)
+	}
+
+	subIDs := parties.Instances[0][3].Group
+	if subIDs == nil || len(subIDs.Instances) != 1 || subIDs.Instances[0][0].Value != "DESK" {
+		t.Errorf("expect nested 802 group with PartySubID DESK, actual %v", subIDs)
+	}
+
+	if actual := parties.Instances[1][0].Value; actual != "CLNT" {
+		t.Errorf("expect CLNT, actual %s", actual)
+	}
+}
+
+func TestFixDecoder_Invalid_GroupCount(t *testing.T) {
+	message := "8=FIX.4.4|9=0|35=D|453=3|448=BRKR|447=D|452=1|448=CLNT|447=D|452=3|55=IBM|10=000|"
+	actual := r.Replace(fd.Decode(message).String())
+	expect := `{"ID":"453","Name":"NoPartyIDs","Value":"3","DecodedValue":"Invalid(expected2)"}`
+
+	if !strings.Contains(actual, expect) {
+		t.Errorf("expect %s in %s", expect, actual)
+	}
+}
+
+func TestMessageValidator(t *testing.T) {
+	v := &fixdecoder.MessageValidator{}
+	if issues := v.Validate(fd.Decode(validfixmessage)); len(issues) != 0 {
+		t.Errorf("expect valid ResendRequest, actual %v", issues)
+	}
+
+	message := "8=FIX.4.4|9=0|35=D|49=A|56=B|34=2|52=20180126-07:39:59.683|11=ORD1|7=1|55=IBM|60=20180126-07:39:59.683|40=1|10=000|"
+	issues := v.Validate(fd.Decode(message))
+	if len(issues) == 0 {
+		t.Fatal("expect invalid NewOrderSingle")
+	}
+
+	if expect, actual := "54,38", issueTags(issues, fixdecoder.MISSINGFIELD); actual != expect {
+		t.Errorf("expect missing %s, actual %s", expect, actual)
+	}
+
+	if expect, actual := "7", issueTags(issues, fixdecoder.FIELDNOTALLOWED); actual != expect {
+		t.Errorf("expect not allowed %s, actual %s", expect, actual)
+	}
+
+	issues = v.Validate(fd.Decode("8=FIX.4.4|9=0|35=6|49=A|56=B|34=2|52=20180126-07:39:59.683|10=000|"))
+	if len(issues) != 1 || issues[0].Code != fixdecoder.UNDEFINEDMSGTYPE || issues[0].Severity != fixdecoder.WARNING {
+		t.Errorf("expect undefined MsgType warning, actual %v", issues)
+	}
+
+	issues = v.Validate(fd.Decode("8=FIX.4.4|9=0|35=ZZ|49=A|56=B|34=2|52=20180126-07:39:59.683|10=000|"))
+	if len(issues) != 1 || issues[0].Code != fixdecoder.UNKNOWNMSGTYPE || issues[0].Severity != fixdecoder.ERROR {
+		t.Errorf("expect unknown MsgType error, actual %v", issues)
+	}
+}
+
+func TestValidationIssue(t *testing.T) {
+	dfs := fd.Decode(invalidfixmessage_checksum)
+	issues := dfs.Validate()
+	if len(issues) != 1 {
+		t.Fatalf("expect 1 issue, actual %v", issues)
+	}
+
+	issue := issues[0]
+	if issue.Tag != 10 || issue.Severity != fixdecoder.ERROR || issue.Code != fixdecoder.INVALIDCHECKSUM || issue.Expected != "036" || issue.Actual != "999" {
+		t.Errorf("expect invalid CheckSum 999, actual %+v", issue)
+	}
+
+	expect := "error 10 invalid-checksum: CheckSum does not match the message (expected 036, actual 999)"
+	if actual := issue.String(); actual != expect {
+		t.Errorf("expect %s, actual %s", expect, actual)
+	}
+
+	classes := dfs[len(dfs)-1].Classes
+	if first, second := dfs.String(), dfs.String(); first != second {
+		t.Errorf("expect %s, actual %s", first, second)
+	}
+
+	if checksum := dfs[len(dfs)-1]; checksum.DecodedValue != "" || checksum.Classes != classes {
+		t.Errorf("expect CheckSum left untouched, actual %q %q", checksum.DecodedValue, checksum.Classes)
+	}
+}
+
+// issueTags the tags of the issues with the given code, comma separated
+func issueTags(issues []*fixdecoder.ValidationIssue, code string) string {
+	tags := make([]string, 0)
+	for _, issue := range issues {
+		if issue.Code == code {
+			tags = append(tags, strconv.Itoa(issue.Tag))
+		}
+	}
+
+	return strings.Join(tags, ",")
+}
+
+func TestScanner(t *testing.T) {
+	s := fixdecoder.NewScanner([]byte(validfixmessage + "\r\n"))
+
+	tags := make([]string, 0)
+	for s.Next() {
+		tags = append(tags, strconv.Itoa(s.Tag())+"="+string(s.Value()))
+	}
+
+	if err := s.Err(); err != nil {
+		t.Fatalf("expect no error, actual %v", err)
+	}
+
+	if expect, actual := strings.Replace(validfixmessage, "\x01", ",", -1), strings.Join(tags, ",")+","; actual != expect {
+		t.Errorf("expect %s, actual %s", expect, actual)
+	}
+
+	s.Reset([]byte("8=FIX.4.4\x0135=0\x01010=000\x01"))
+	for s.Next() {
+	}
+
+	var malformed *fixdecoder.ErrMalformedField
+	if err := s.Err(); !errors.As(err, &malformed) || malformed.Offset != 15 {
+		t.Errorf("expect malformed field at offset 15, actual %v", err)
+	}
+}
+
+func TestFixDecoder_DataField(t *testing.T) {
+	payload := "a\x01b|c=d;"
+	message := "8=FIX.4.4\x019=0\x0135=B\x0195=8\x0196=" + payload + "\x0158=news\x0110=000\x01"
+
+	msg, err := fd.DecodeE(message)
+	if err != nil {
+		t.Fatalf("expect no error, actual %v", err)
+	}
+
+	if expect, actual := payload, string(msg.Fields[4].Bytes()); actual != expect {
+		t.Errorf("expect %q, actual %q", expect, actual)
+	}
+
+	if expect, actual := "58", msg.Fields[5].FieldID; actual != expect {
+		t.Errorf("expect %s, actual %s", expect, actual)
+	}
+
+	if expect, actual := payload, fd.Decode(message)[4].Value; actual != expect {
+		t.Errorf("expect %q, actual %q", expect, actual)
+	}
+
+	actual := r.Replace(fd.Decode("8=FIX.4.4|9=0|35=B|95=5|96=abc|58=news|10=000|").String())
+	expect := `{"ID":"95","Name":"RawDataLength","Value":"5","DecodedValue":"Invalid(expected3)"}`
+	if !strings.Contains(actual, expect) {
+		t.Errorf("expect %s in %s", expect, actual)
+	}
+}
+
+func TestFixDecoder_Delimiter(t *testing.T) {
+	// the delimiter is detected from what follows BeginString, so ';' is part of Text
+	message := "8=FIX.4.4|9=0|35=0|58=a;b|10=000|"
+	if expect, actual := "a;b", fd.Decode(message)[3].Value; actual != expect {
+		t.Errorf("expect %s, actual %s", expect, actual)
+	}
+
+	for _, delimiter := range []string{fixdecoder.PIPE, fixdecoder.CARETSOH, fixdecoder.LITERALSOH} {
+		message := strings.Replace(validfixmessage, "\x01", delimiter, -1)
+
+		// BodyLength and CheckSum are validated as on the wire
+		actual := r.Replace(fd.Decode(message).String())
+		if expect := r.Replace(fd.Decode(validfixmessage).String()); actual != expect {
+			t.Errorf("expect %s, actual %s", expect, actual)
+		}
+
+		msg, err := fixdecoder.NewFixDecoder(fixdecoder.WithDelimiter(delimiter)).DecodeE(message)
+		if err != nil || msg.Delimiter != delimiter || len(msg.Fields) != 10 {
+			t.Errorf("expect message delimited by %s, actual %v %v", delimiter, msg, err)
+		}
+	}
+
+	// an explicit delimiter splits on nothing else
+	if _, err := fixdecoder.NewFixDecoder(fixdecoder.WithDelimiter(fixdecoder.SOH)).DecodeE(strings.Replace(validfixmessage, "\x01", "|", -1)); err == nil {
+		t.Error("expect error, actual nil")
+	}
+}
+
+func BenchmarkScanner(b *testing.B) {
+	message := []byte(validfixmessage)
+	var s fixdecoder.Scanner
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(message)))
+	for i := 0; i < b.N; i++ {
+		s.Reset(message)
+		for s.Next() {
+		}
+		if s.Err() != nil {
+			b.Fatal(s.Err())
+		}
+	}
+}
+
+func BenchmarkScanner_Dictionary(b *testing.B) {
+	message := []byte(validfixmessage)
+	dictionary := fixdecoder.DefaultDictionary()
+	var s fixdecoder.Scanner
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(message)))
+	for i := 0; i < b.N; i++ {
+		s.Reset(message)
+		for s.Next() {
+			if field := dictionary.FieldByTag(s.Tag()); field == nil || field.Name == "" {
+				b.Fatalf("unknown tag %d", s.Tag())
+			}
+		}
+	}
+}
+
+func BenchmarkFixDecoder_Decode(b *testing.B) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(validfixmessage)))
+	for i := 0; i < b.N; i++ {
+		fd.Decode(validfixmessage)
+	}
+}
+
+func TestFixDecoder_Decode_Allocs(t *testing.T) {
+	fd.Decode(validfixmessage)
+	if allocs := testing.AllocsPerRun(100, func() { fd.Decode(validfixmessage) }); allocs > 3 {
+		t.Errorf("expect at most 3 allocations, actual %v", allocs)
+	}
+}
+
+func BenchmarkFixDecoder_DecodeE(b *testing.B) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(validfixmessage)))
+	for i := 0; i < b.N; i++ {
+		if _, err := fd.DecodeE(validfixmessage); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestEnumValidator(t *testing.T) {
+	dfs := fd.Decode("8=FIX.4.4|9=0|35=D|54=Z|18=1 A G|59=0|21=7|65=XYZ|10=000|")
+	if expect, actual := "Not Held, No Cross, All Or None", dfs[4].DecodedValue; actual != expect {
+		t.Errorf("expect %s, actual %s", expect, actual)
+	}
+
+	v := &fixdecoder.EnumValidator{}
+	if expect, actual := "54,21", issueTags(v.Validate(dfs), fixdecoder.INVALIDENUMVALUE); actual != expect {
+		t.Errorf("expect invalid %s, actual %s", expect, actual)
+	}
+
+	dfs = fd.Decode("8=FIX.4.4|9=0|35=D|18=1 x 9|21=100|10=000|")
+	issues := v.Validate(dfs)
+	if len(issues) != 2 || issues[0].Message != "value x not enumerated for ExecInst" {
+		t.Errorf("expect invalid ExecInst x and HandlInst 100, actual %v", issues)
+	}
+
+	v.Extensions = map[string][]fixdecoder.ValueRange{"18": {{From: "a", To: "z"}}, "21": {{From: "100"}}}
+	if issues := v.Validate(dfs); len(issues) != 0 {
+		t.Errorf("expect user-defined values accepted, actual %v", issues)
+	}
+}
+
+func TestTypeValidator(t *testing.T) {
+	v := fixdecoder.TypeValidator{}
+	valid := "8=FIX.4.4|9=0|35=D|34=1|52=20260301-10:00:00.123|11=ORD1|38=100|44=12.50|54=1|15=EUR|421=FR|207=XPAR|43=N|75=20260301|200=202603|10=000|"
+	if issues := v.Validate(fd.Decode(valid)); len(issues) != 0 {
+		t.Errorf("expect valid, actual %v", issues)
+	}
+
+	invalid := "8=FIX.4.4|9=0|35=D|34=-1|52=20260301-10:00|11=ORD1|38=abc|44=1e3|54=12|15=EURO|421=XX|207=paris|43=T|75=2026031|200=202603w9|10=000|"
+	issues := v.Validate(fd.Decode(invalid))
+	if expect, actual := "34,52,38,44,54,15,421,207,43,75,200", issueTags(issues, fixdecoder.INVALIDTYPE); actual != expect {
+		t.Errorf("expect invalid %s, actual %s", expect, actual)
+	}
+
+	if expect, actual := "not a valid QTY", issues[2].Message; actual != expect {
+		t.Errorf("expect %s, actual %s", expect, actual)
+	}
+
+	// well formed, but not a registered market identifier code
+	if expect, actual := "207", issueTags(v.Validate(fd.Decode("8=FIX.4.4|9=0|35=D|207=ZZZZ|10=000|")), fixdecoder.INVALIDTYPE); actual != expect {
+		t.Errorf("expect invalid %s, actual %s", expect, actual)
+	}
+}
+
+func TestTypeValidator_Types(t *testing.T) {
+	dictionary := fixdecoder.NewDictionary("FIX.5.0SP2")
+	for _, field := range []*fixdecoder.FieldDefinition{
+		{Tag: "18", Name: "ExecInst", Type: "MULTIPLECHARVALUE"},
+		{Tag: "1079", Name: "MaturityTime", Type: "TZTIMEONLY"},
+		{Tag: "1132", Name: "TZTransactTime", Type: "TZTIMESTAMP"},
+		{Tag: "6000", Name: "LocalTime", Type: "LOCALMKTTIME"},
+	} {
+		dictionary.AddField(field)
+	}
+	decoder := fixdecoder.NewFixDecoder(fixdecoder.WithDictionary(dictionary))
+
+	for _, test := range []struct {
+		field   string
+		valid   []string
+		invalid []string
+	}{
+		{"18", []string{"1", "1 A G"}, []string{"", "1A", "1  A", "1 AB"}},
+		{"1079", []string{"10:00", "10:00:00", "10:00:00.123Z", "10:00+01", "10:00:00-05:30"}, []string{"10", "25:00Z", "10:00+15", "10:00+01:60", "10:00*01"}},
+		{"1132", []string{"20260301-10:00Z", "20260301-10:00:00.123456+01:00", "20260301-10:00:00"}, []string{"20260301", "20260301-10:00+1", "20261301-10:00Z"}},
+		{"6000", []string{"10:00:00", "10:00:00.123"}, []string{"10:00", "10:00:00Z", "10:00:60"}},
+	} {
+		for _, value := range test.valid {
+			dfs := decoder.Decode("8=FIXT.1.1|9=0|35=D|" + test.field + "=" + value + "|10=000|")
+			if issues := (fixdecoder.TypeValidator{}).Validate(dfs); len(issues) != 0 {
+				t.Errorf("expect %s=%s valid, actual %v", test.field, value, issues)
+			}
+		}
+
+		for _, value := range test.invalid {
+			dfs := decoder.Decode("8=FIXT.1.1|9=0|35=D|" + test.field + "=" + value + "|10=000|")
+			if actual := issueTags((fixdecoder.TypeValidator{}).Validate(dfs), fixdecoder.INVALIDTYPE); actual != test.field {
+				t.Errorf("expect %s=%s invalid, actual %s", test.field, value, actual)
+			}
+		}
+	}
+
+	msg, _ := decoder.DecodeE("8=FIXT.1.1|9=0|35=D|1132=20260301-10:00:00-05:00|10=000|")
+	if tm, err := msg.GetTime(1132); err != nil || tm.UTC().Format(fixdecoder.UTCTIMESTAMPLAYOUT) != "20260301-15:00:00" {
+		t.Errorf("expect 20260301-15:00:00 UTC, actual %v %v", tm, err)
+	}
+}
+
+func TestFieldOrderValidator(t *testing.T) {
+	v := &fixdecoder.FieldOrderValidator{}
+	if issues := v.Validate(fd.Decode(validfixmessage)); len(issues) != 0 {
+		t.Errorf("expect valid, actual %v", issues)
+	}
+
+	message := "8=FIX.4.4|35=D|9=0|49=A|11=ORD1|34=2|93=3|89=abc|55=IBM|10=000|52=20260301-10:00:00|"
+	issues := v.Validate(fd.Decode(message))
+
+	positions := make([]string, 0)
+	for _, issue := range issues {
+		positions = append(positions, strconv.Itoa(issue.Tag)+"@"+strconv.Itoa(issue.Position))
+	}
+
+	if expect, actual := "9@3,35@2,34@6,93@7,89@8,10@10,52@11", strings.Join(positions, ","); actual != expect {
+		t.Errorf("expect %s, actual %s", expect, actual)
+	}
+
+	if expect, actual := "header field at position 6, after body field 11 at position 5", issues[2].Message; actual != expect {
+		t.Errorf("expect %s, actual %s", expect, actual)
+	}
+
+	issues = v.Validate(fd.Decode("8=FIX.4.4|9=0|35=0|93=3|89=abc|49=A|10=000|"))
+	if len(issues) != 2 || issues[0].Tag != 93 || issues[1].Tag != 89 {
+		t.Fatalf("expect misplaced SignatureLength and Signature, actual %v", issues)
+	}
+	if expect, actual := "trailer field at position 4, before 49 at position 6", issues[0].Message; actual != expect {
+		t.Errorf("expect %s, actual %s", expect, actual)
+	}
+}
+
+func TestDuplicateFieldValidator(t *testing.T) {
+	message := "8=FIX.4.4|9=0|35=D|11=ORD1|453=2|448=BRKR|447=D|452=1|448=CLNT|447=D|452=3|55=IBM|11=ORD2|10=000|"
+	issues := fixdecoder.DuplicateFieldValidator{}.Validate(fd.Decode(message))
+	if len(issues) != 1 || issues[0].Tag != 11 || issues[0].Position != 13 || issues[0].Actual != "ORD2" {
+		t.Fatalf("expect duplicate ClOrdID at position 13, actual %v", issues)
+	}
+
+	msg, _ := fd.DecodeE(message)
+	if clOrdID := msg.Field(11); clOrdID == nil || clOrdID.Value != "ORD1" {
+		t.Errorf("expect first ClOrdID by default")
+	}
+
+	last, _ := fixdecoder.NewFixDecoder(fixdecoder.WithLookupPolicy(fixdecoder.LOOKUPLAST)).DecodeE(message)
+	if clOrdID := last.Field(11); clOrdID == nil || clOrdID.Value != "ORD2" {
+		t.Errorf("expect last ClOrdID")
+	}
+
+	strict, _ := fixdecoder.NewFixDecoder(fixdecoder.WithLookupPolicy(fixdecoder.LOOKUPERROR)).DecodeE(message)
+	var ambiguous *fixdecoder.ErrAmbiguousField
+	if _, err := strict.Lookup(11); !errors.As(err, &ambiguous) || ambiguous.Count != 2 {
+		t.Errorf("expect ambiguous ClOrdID, actual %v", err)
+	}
+
+	if symbol, err := strict.Lookup(55); err != nil || symbol.Value != "IBM" {
+		t.Errorf("expect IBM, actual %v", err)
+	}
+}
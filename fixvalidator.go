@@ -1,10 +1,72 @@
 package fixdecoder
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 )
 
+// Severities of validation issues
+const (
+	ERROR   = "error"   // The message is not valid
+	WARNING = "warning" // The message is valid, but suspicious
+)
+
+// Codes of validation issues
+const (
+	INVALIDBODYLENGTH  = "invalid-body-length"  // BodyLength <9> does not match the length of the message
+	INVALIDCHECKSUM    = "invalid-checksum"     // CheckSum <10> does not match the bytes of the message
+	INVALIDGROUPCOUNT  = "invalid-group-count"  // A NUMINGROUP field does not match the number of instances of its repeating group
+	INVALIDDATALENGTH  = "invalid-data-length"  // A LENGTH field does not match the length of its DATA field
+	MISSINGLENGTHFIELD = "missing-length-field" // A DATA field does not come right after its LENGTH field
+	MISSINGFIELD       = "missing-field"        // A required field is missing
+	INVALIDENUMVALUE   = "invalid-enum-value"   // A value is not one of the enumerated values of its field
+	INVALIDTYPE        = "invalid-type"         // A value does not conform to the type of its field
+	MISPLACEDFIELD     = "misplaced-field"      // A field is out of the header, body, trailer order
+	DUPLICATEFIELD     = "duplicate-field"      // A tag appears more than once outside of a repeating group
+	FIELDNOTALLOWED    = "field-not-allowed"    // A field is not defined for the message type
+	UNKNOWNMSGTYPE     = "unknown-msg-type"     // MsgType <35> is not a message type of the FIX version
+	UNDEFINEDMSGTYPE   = "undefined-msg-type"   // MsgType <35> is known, but the dictionary has no definition to check the message against
+)
+
+// ValidationIssue a problem found by a validator. The decoded fields are left untouched
+type ValidationIssue struct {
+	Tag      int    // Tag of the field concerned
+	Severity string // ERROR or WARNING
+	Code     string // e.g. INVALIDCHECKSUM
+	Message  string
+	Expected string        // Expected value, if any
+	Actual   string        // Actual value, if any
+	Position int           // Position of the field within the message, from 1; 0 if not relevant
+	Field    *DecodedField `json:"-"` // The field concerned, nil if it is missing from the message
+}
+
+// String the issue, e.g. "error 10 invalid-checksum: CheckSum does not match the message (expected 036, actual 999)"
+func (i *ValidationIssue) String() string {
+	result := fmt.Sprintf("%s %d %s: %s", i.Severity, i.Tag, i.Code, i.Message)
+	if i.Expected != "" {
+		result += fmt.Sprintf(" (expected %s, actual %s)", i.Expected, i.Actual)
+	}
+
+	return result
+}
+
+// newIssue new error issue about a field
+func newIssue(field *DecodedField, code, message, expected string) *ValidationIssue {
+	return &ValidationIssue{Tag: field.Tag, Severity: ERROR, Code: code, Message: message, Expected: expected, Actual: field.Value, Field: field}
+}
+
+// missingIssue new error issue about a missing field
+func missingIssue(fieldID, message string) *ValidationIssue {
+	tag, err := strconv.Atoi(fieldID)
+	if err != nil {
+		tag = -1
+	}
+
+	return &ValidationIssue{Tag: tag, Severity: ERROR, Code: MISSINGFIELD, Message: message}
+}
+
 // ValidatorFactory validator factory
 type ValidatorFactory struct{}
 
@@ -13,24 +75,80 @@ func NewValidatorFactory() *ValidatorFactory {
 	return &ValidatorFactory{}
 }
 
-// CreateValidators create validators
+// CreateValidators create the registered validators enabled by default, see RegisterValidator
 func (vf *ValidatorFactory) CreateValidators() []Validator {
-	return []Validator{BodyLengthValidator{}, CheckSumValidator{}}
+	validators := make([]Validator, 0)
+	for _, registered := range registry.snapshot() {
+		if registered.enabled {
+			validators = append(validators, registered.validator)
+		}
+	}
+
+	return validators
 }
 
-// Validator field validator. For example, checksum validation, body length validation
+// Validator field validator. For example, checksum validation, body length validation. The message is valid if no issue is returned
 type Validator interface {
-	Validate(DecodedFields) bool
+	Validate(DecodedFields) []*ValidationIssue
+}
+
+// DictionaryValidator a validator checking messages against a dictionary. FixDecoder.Validate calls ValidateWith with the dictionary the decoder
+// picks for the message (see WithDictionary, WithVersionDictionary and WithDefaultApplVerID); a nil dictionary stands for the built-in one
+type DictionaryValidator interface {
+	Validator
+	ValidateWith(DecodedFields, *Dictionary) []*ValidationIssue
+}
+
+// validationDictionary the dictionary to check a message against: the one set on the validator, else the one of the decoder,
+// else the built-in dictionary of the version of the message
+func validationDictionary(own, decoder *Dictionary, dfs DecodedFields) *Dictionary {
+	if own != nil {
+		return own
+	}
+
+	if decoder != nil {
+		return decoder
+	}
+
+	return SelectDictionary(fieldValue(dfs, BEGINSTRING), fieldValue(dfs, APPLVERID), "9")
+}
+
+// Validate run the registered validators enabled by default. FixDecoder.Validate applies the profile of the decoder instead
+func (dfs DecodedFields) Validate() []*ValidationIssue {
+	var profile *ValidationProfile
+	return profile.Validate(dfs)
+}
+
+// Describe the decoded value of a field given the validation issues of its message: "Invalid (expected 036)" if an issue concerns the field,
+// "Valid" for BodyLength <9> and CheckSum <10> otherwise
+func (df *DecodedField) Describe(issues []*ValidationIssue) string {
+	for _, issue := range issues {
+		if issue.Field != df {
+			continue
+		}
+
+		if issue.Expected != "" {
+			return fmt.Sprintf("Invalid (expected %v)", issue.Expected)
+		}
+
+		return fmt.Sprintf("Invalid (%s)", issue.Message)
+	}
+
+	if df.FieldID == BODYLENGTH || df.FieldID == CHECKSUM {
+		return "Valid"
+	}
+
+	return df.DecodedValue
 }
 
 // BodyLengthValidator BodyLength is the character count starting at tag 35 (included, MsgType) all the way to tag 10 (excluded). SOH delimiters do count in body length (length = 1).
 type BodyLengthValidator struct{}
 
 // Validate body length validate
-func (v BodyLengthValidator) Validate(dfs DecodedFields) bool {
+func (v BodyLengthValidator) Validate(dfs DecodedFields) []*ValidationIssue {
 	// pass if no data
 	if len(dfs) == 0 {
-		return true
+		return nil
 	}
 
 	length := 0
@@ -50,16 +168,17 @@ func (v BodyLengthValidator) Validate(dfs DecodedFields) bool {
 		length += len(line.Raw())
 	}
 
+	// invalid if there is no BodyLength to check
+	if bodylengthfield == nil {
+		return []*ValidationIssue{missingIssue(BODYLENGTH, "BodyLength is missing")}
+	}
+
 	bodylengthfieldvalue, _ := strconv.Atoi(bodylengthfield.Value)
 	if bodylengthfieldvalue == length {
-		bodylengthfield.Classes += " Valid"
-		bodylengthfield.DecodedValue = "Valid"
-		return true
+		return nil
 	}
 
-	bodylengthfield.Classes += " Invalid"
-	bodylengthfield.DecodedValue = fmt.Sprintf("Invalid (expected %v)", length)
-	return false
+	return []*ValidationIssue{newIssue(bodylengthfield, INVALIDBODYLENGTH, "BodyLength does not match the message", strconv.Itoa(length))}
 }
 
 // CheckSumValidator checksum
@@ -67,10 +186,10 @@ type CheckSumValidator struct{}
 
 // Validate checksum validate
 // https://www.onixs.biz/fix-dictionary/4.2/app_b.html
-func (v CheckSumValidator) Validate(dfs DecodedFields) bool {
+func (v CheckSumValidator) Validate(dfs DecodedFields) []*ValidationIssue {
 	// pass if no data
 	if len(dfs) == 0 {
-		return true
+		return nil
 	}
 
 	sum := 0
@@ -87,17 +206,480 @@ func (v CheckSumValidator) Validate(dfs DecodedFields) bool {
 		}
 	}
 
+	// invalid if there is no CheckSum to check
+	if checksumfield == nil {
+		return []*ValidationIssue{missingIssue(CHECKSUM, "CheckSum is missing")}
+	}
+
+	checksum := formatCheckSum(sum)
+
+	if checksumfield.Value == checksum {
+		return nil
+	}
+
+	return []*ValidationIssue{newIssue(checksumfield, INVALIDCHECKSUM, "CheckSum does not match the message", checksum)}
+}
+
+// formatCheckSum the CheckSum <10> value of a message whose bytes sum up to sum
+func formatCheckSum(sum int) string {
 	// Modulo 256 + pad up to 3 characters with zero
 	modulo := "00" + strconv.Itoa(sum%256)
-	checksum := modulo[len(modulo)-3:]
+	return modulo[len(modulo)-3:]
+}
 
-	if checksumfield.Value == checksum {
-		checksumfield.Classes += " Valid"
-		checksumfield.DecodedValue = "Valid"
-		return true
+// GroupCountValidator the value of a NUMINGROUP field (e.g. 453 NoPartyIDs) must match the number of instances of its repeating group
+type GroupCountValidator struct{}
+
+// Validate group count validate
+func (v GroupCountValidator) Validate(dfs DecodedFields) []*ValidationIssue {
+	var issues []*ValidationIssue
+	for _, line := range dfs {
+		if line.Group == nil {
+			continue
+		}
+
+		instances := len(line.Group.Instances)
+		if count, err := strconv.Atoi(line.Value); err != nil || count != instances {
+			issues = append(issues, newIssue(line, INVALIDGROUPCOUNT, "count does not match the instances of the repeating group", strconv.Itoa(instances)))
+		}
+	}
+
+	return issues
+}
+
+// DataLengthValidator a DATA field (e.g. RawData <96>) must come right after its LENGTH field (e.g. RawDataLength <95>), and be exactly as long as its value
+type DataLengthValidator struct{}
+
+// Validate data length validate
+func (v DataLengthValidator) Validate(dfs DecodedFields) []*ValidationIssue {
+	var issues []*ValidationIssue
+	for i, line := range dfs {
+		if line.Field == nil || line.Field.Type != "DATA" {
+			continue
+		}
+
+		var lengthfield *DecodedField
+		if i > 0 && dfs[i-1].Field != nil && dfs[i-1].Field.Type == "LENGTH" {
+			lengthfield = dfs[i-1]
+		}
+
+		if lengthfield == nil {
+			issues = append(issues, newIssue(line, MISSINGLENGTHFIELD, "missing length field", ""))
+			continue
+		}
+
+		if length, err := strconv.Atoi(lengthfield.Value); err != nil || length != len(line.Value) {
+			issues = append(issues, newIssue(lengthfield, INVALIDDATALENGTH, "length does not match its data field", strconv.Itoa(len(line.Value))))
+		}
+	}
+
+	return issues
+}
+
+// ValueRange a range of user-defined enumerated values, compared as numbers if they all are, as strings otherwise. To is empty for no upper bound,
+// e.g. {From: "100"} for values from 100 up, or {From: "a", To: "z"} for lower case CHAR values
+type ValueRange struct {
+	From string
+	To   string
+}
+
+// contains whether a value is within the range
+func (r ValueRange) contains(value string) bool {
+	v, err1 := strconv.Atoi(value)
+	from, err2 := strconv.Atoi(r.From)
+	to, err3 := strconv.Atoi(r.To)
+	if err1 == nil && err2 == nil && (err3 == nil || r.To == "") {
+		return v >= from && (r.To == "" || v <= to)
+	}
+
+	return value >= r.From && (r.To == "" || value <= r.To)
+}
+
+// EnumValidator the values of fields with enumerated values (e.g. CHAR, INT, STRING) must be one of them. MULTIPLEVALUESTRING values are checked one by one.
+// Fields whose dictionary definition allows other values are not checked
+type EnumValidator struct {
+	Dictionary *Dictionary             // If nil, the dictionary of the decoder, or the built-in one of the version of the message
+	Extensions map[string][]ValueRange // User-defined values accepted on top of the enumerated ones, group by tag
+}
+
+// Validate enumerated values validate
+func (v *EnumValidator) Validate(dfs DecodedFields) []*ValidationIssue {
+	return v.ValidateWith(dfs, nil)
+}
+
+// ValidateWith enumerated values validate against the dictionary of the decoder, see DictionaryValidator
+func (v *EnumValidator) ValidateWith(dfs DecodedFields, dictionary *Dictionary) []*ValidationIssue {
+	dictionary = validationDictionary(v.Dictionary, dictionary, dfs)
+
+	var issues []*ValidationIssue
+	for _, line := range dfs {
+		field := dictionary.FieldByTag(line.Tag)
+		if field == nil || len(field.Values) == 0 || field.AllowOtherValues {
+			continue
+		}
+
+		values := []string{line.Value}
+		if isMultipleValue(field.Type) {
+			values = strings.Fields(line.Value)
+		}
+
+		invalid := make([]string, 0)
+		for _, value := range values {
+			if _, found := field.Values[value]; !found && !v.extension(line.FieldID, value) {
+				invalid = append(invalid, value)
+			}
+		}
+
+		if len(invalid) > 0 {
+			issues = append(issues, newIssue(line, INVALIDENUMVALUE, "value "+strings.Join(invalid, " ")+" not enumerated for "+field.Name, ""))
+		}
+	}
+
+	return issues
+}
+
+// Configure a new enum validator from the options of a profile rule: the path of a QuickFIX "dictionary" and the "extensions" of the values,
+// e.g. {"extensions": {"18": [{"from": "a", "to": "z"}]}}
+func (v *EnumValidator) Configure(options json.RawMessage) (Validator, error) {
+	config := struct {
+		Dictionary string
+		Extensions map[string][]ValueRange
+	}{}
+	if err := json.Unmarshal(options, &config); err != nil {
+		return nil, err
+	}
+
+	dictionary, err := configureDictionary(config.Dictionary, v.Dictionary)
+	return &EnumValidator{Dictionary: dictionary, Extensions: config.Extensions}, err
+}
+
+// configureDictionary the dictionary of a configured validator: the QuickFIX dictionary file at path, current if path is empty
+func configureDictionary(path string, current *Dictionary) (*Dictionary, error) {
+	if path == "" {
+		return current, nil
+	}
+
+	return LoadQuickFIXDictionaryFile(path)
+}
+
+// configureDictionaryOnly the dictionary of a validator whose only option is the path of a QuickFIX "dictionary"
+func configureDictionaryOnly(options json.RawMessage, current *Dictionary) (*Dictionary, error) {
+	config := struct {
+		Dictionary string
+	}{}
+	if err := json.Unmarshal(options, &config); err != nil {
+		return nil, err
+	}
+
+	return configureDictionary(config.Dictionary, current)
+}
+
+// extension whether a value is within the user-defined ranges of a field
+func (v *EnumValidator) extension(fieldID, value string) bool {
+	for _, r := range v.Extensions[fieldID] {
+		if r.contains(value) {
+			return true
+		}
 	}
 
-	checksumfield.Classes += " Invalid"
-	checksumfield.DecodedValue = fmt.Sprintf("Invalid (expected %v)", checksum)
 	return false
 }
+
+// TypeValidator the value of every field must conform to its FIX type: integers, decimals without exponent, single byte CHAR and MULTIPLECHARVALUE
+// values, Y/N BOOLEAN, ISO 4217 currencies, ISO 3166 countries, ISO 10383 market identifier codes, dates, times of day
+// and timestamps, time zones included.
+// Fields of unknown type are not checked
+type TypeValidator struct{}
+
+// Validate data type validate
+func (v TypeValidator) Validate(dfs DecodedFields) []*ValidationIssue {
+	var issues []*ValidationIssue
+	for _, line := range dfs {
+		if line.Field == nil || conforms(line) {
+			continue
+		}
+
+		issues = append(issues, newIssue(line, INVALIDTYPE, "not a valid "+line.Field.Type, ""))
+	}
+
+	return issues
+}
+
+// conforms whether the value of a field conforms to its type
+func conforms(df *DecodedField) bool {
+	var err error
+	switch df.Field.Type {
+	case "INT":
+		_, err = df.Int()
+	case "SEQNUM", "LENGTH", "NUMINGROUP":
+		var n int
+		if n, err = df.Int(); err == nil && n < 0 {
+			return false
+		}
+	case "TAGNUM":
+		var n int
+		if n, err = df.Int(); err == nil && n <= 0 {
+			return false
+		}
+	case "DAYOFMONTH":
+		var n int
+		if n, err = df.Int(); err == nil && (n < 1 || n > 31) {
+			return false
+		}
+	case "PRICE", "QTY", "AMT", "FLOAT", "PERCENTAGE", "PRICEOFFSET":
+		_, err = df.Decimal()
+	case "CHAR":
+		return len(df.Value) == 1
+	case "MULTIPLECHARVALUE":
+		for _, value := range strings.Split(df.Value, " ") {
+			if len(value) != 1 {
+				return false
+			}
+		}
+	case "BOOLEAN":
+		_, err = df.Bool()
+	case "CURRENCY":
+		return currencyCodes[df.Value]
+	case "COUNTRY":
+		return countryCodes[df.Value]
+	case "EXCHANGE":
+		return isMIC(df.Value)
+	case "UTCTIMESTAMP", "UTCTIMEONLY", "TZTIMESTAMP", "TZTIMEONLY", "LOCALMKTTIME":
+		_, err = df.Time()
+	case "LOCALMKTDATE", "UTCDATEONLY", "UTCDATE":
+		_, err = df.Date()
+	case "MONTHYEAR":
+		_, err = df.MonthYear()
+	}
+
+	return err == nil
+}
+
+// FieldOrderValidator BeginString <8>, BodyLength <9> and MsgType <35> must be the first three fields and CheckSum <10> the last one,
+// header fields must come before body fields, and trailer fields (e.g. SignatureLength <93>, Signature <89>) after them
+type FieldOrderValidator struct {
+	Dictionary *Dictionary // If nil, the dictionary of the decoder, or the built-in one of the version of the message
+}
+
+// Validate field order validate
+func (v *FieldOrderValidator) Validate(dfs DecodedFields) []*ValidationIssue {
+	return v.ValidateWith(dfs, nil)
+}
+
+// Configure a new field order validator from the options of a profile rule: the path of a QuickFIX "dictionary"
+func (v *FieldOrderValidator) Configure(options json.RawMessage) (Validator, error) {
+	dictionary, err := configureDictionaryOnly(options, v.Dictionary)
+	return &FieldOrderValidator{Dictionary: dictionary}, err
+}
+
+// ValidateWith field order validate against the dictionary of the decoder, see DictionaryValidator
+func (v *FieldOrderValidator) ValidateWith(dfs DecodedFields, dictionary *Dictionary) []*ValidationIssue {
+	dictionary = validationDictionary(v.Dictionary, dictionary, dfs)
+
+	header, trailer := make(map[string]bool), make(map[string]bool)
+	for _, fieldID := range dictionary.Header.Fields {
+		allowGroup(dictionary, fieldID, header)
+	}
+	for _, fieldID := range dictionary.Trailer.Fields {
+		allowGroup(dictionary, fieldID, trailer)
+	}
+
+	var issues []*ValidationIssue
+	misplaced := func(i int, message string) {
+		issue := newIssue(dfs[i], MISPLACEDFIELD, message, "")
+		issue.Position = i + 1
+		issues = append(issues, issue)
+	}
+
+	for position, fieldID := range []string{BEGINSTRING, BODYLENGTH, MSGTYPE} {
+		for i, line := range dfs {
+			if line.FieldID == fieldID {
+				if i != position {
+					misplaced(i, fmt.Sprintf("%s at position %d, expected position %d", line.Field.Name, i+1, position+1))
+				}
+				break
+			}
+		}
+	}
+
+	body, trailers := -1, []int(nil)
+	for i, line := range dfs {
+		if line.FieldID != CHECKSUM && !trailer[line.FieldID] {
+			for _, t := range trailers {
+				misplaced(t, fmt.Sprintf("trailer field at position %d, before %s at position %d", t+1, line.FieldID, i+1))
+			}
+			trailers = trailers[:0]
+		}
+
+		switch {
+		case line.FieldID == BEGINSTRING || line.FieldID == BODYLENGTH || line.FieldID == MSGTYPE:
+		case line.FieldID == CHECKSUM:
+			if i != len(dfs)-1 {
+				misplaced(i, fmt.Sprintf("CheckSum at position %d, expected last", i+1))
+			}
+		case trailer[line.FieldID]:
+			trailers = append(trailers, i)
+		case header[line.FieldID] && body >= 0:
+			misplaced(i, fmt.Sprintf("header field at position %d, after body field %s at position %d", i+1, dfs[body].FieldID, body+1))
+		case body < 0 && !header[line.FieldID]:
+			body = i
+		}
+	}
+
+	return issues
+}
+
+// DuplicateFieldValidator a tag must appear only once, unless it is a member of a repeating group. Every occurrence after the first one is reported
+type DuplicateFieldValidator struct{}
+
+// Validate duplicate field validate
+func (v DuplicateFieldValidator) Validate(dfs DecodedFields) []*ValidationIssue {
+	members := make(map[*DecodedField]bool)
+	for _, line := range dfs {
+		groupMembers(line.Group, members)
+	}
+
+	var issues []*ValidationIssue
+	first := make(map[string]int)
+	for i, line := range dfs {
+		if members[line] {
+			continue
+		}
+
+		previous, found := first[line.FieldID]
+		if !found {
+			first[line.FieldID] = i
+			continue
+		}
+
+		issue := newIssue(line, DUPLICATEFIELD, fmt.Sprintf("tag %s repeated at position %d, first at position %d", line.FieldID, i+1, previous+1), "")
+		issue.Position = i + 1
+		issues = append(issues, issue)
+	}
+
+	return issues
+}
+
+// groupMembers add the fields of the instances of a repeating group to members, nested groups included
+func groupMembers(group *DecodedGroup, members map[*DecodedField]bool) {
+	if group == nil {
+		return
+	}
+
+	for _, instance := range group.Instances {
+		for _, member := range instance {
+			members[member] = true
+			groupMembers(member.Group, members)
+		}
+	}
+}
+
+// MessageValidator checks a message against the definition of its MsgType <35>: the required fields of the header, body and trailer must be present,
+// and only fields defined for the message type may appear. A MsgType which is not one of the values of the MsgType field of the dictionary is an error;
+// a known MsgType without a definition is reported as a warning, and the message is not checked any further
+type MessageValidator struct {
+	Dictionary *Dictionary // If nil, the dictionary of the decoder, or the built-in one of the version of the message
+}
+
+// Validate message definition validate
+func (v *MessageValidator) Validate(dfs DecodedFields) []*ValidationIssue {
+	return v.ValidateWith(dfs, nil)
+}
+
+// Configure a new message validator from the options of a profile rule: the path of a QuickFIX "dictionary"
+func (v *MessageValidator) Configure(options json.RawMessage) (Validator, error) {
+	dictionary, err := configureDictionaryOnly(options, v.Dictionary)
+	return &MessageValidator{Dictionary: dictionary}, err
+}
+
+// ValidateWith message definition validate against the dictionary of the decoder, see DictionaryValidator
+func (v *MessageValidator) ValidateWith(dfs DecodedFields, dictionary *Dictionary) []*ValidationIssue {
+	var issues []*ValidationIssue
+
+	var msgTypeField *DecodedField
+	msgType := ""
+	present := make(map[string]bool, len(dfs))
+	for _, line := range dfs {
+		if line.FieldID == MSGTYPE && !present[MSGTYPE] {
+			msgType, msgTypeField = line.Value, line
+		}
+		present[line.FieldID] = true
+	}
+
+	dictionary = validationDictionary(v.Dictionary, dictionary, dfs)
+
+	if msgTypeField == nil {
+		return append(issues, missingIssue(MSGTYPE, "required field is missing"))
+	}
+
+	definition := dictionary.Message(msgType)
+	if definition == nil {
+		if field := dictionary.Field(MSGTYPE); field != nil && len(field.Values) > 0 {
+			if _, found := field.Values[msgType]; !found {
+				return append(issues, newIssue(msgTypeField, UNKNOWNMSGTYPE, "unknown MsgType "+msgType, ""))
+			}
+		}
+
+		issue := newIssue(msgTypeField, UNDEFINEDMSGTYPE, "no definition for MsgType "+msgType+", message not checked", "")
+		issue.Severity = WARNING
+		return append(issues, issue)
+	}
+
+	allowed := make(map[string]bool)
+	for _, section := range []*Definition{dictionary.Header, definition, dictionary.Trailer} {
+		required, _ := definitionRequirements(dictionary, section, present, allowed)
+		for _, fieldID := range required {
+			if !present[fieldID] {
+				issues = append(issues, missingIssue(fieldID, "required field is missing"))
+			}
+		}
+	}
+
+	for _, line := range dfs {
+		if !allowed[line.FieldID] {
+			issues = append(issues, newIssue(line, FIELDNOTALLOWED, "field not defined for MsgType "+msgType, ""))
+		}
+	}
+
+	return issues
+}
+
+// definitionRequirements walk a header, trailer, message or component definition. Its fields, repeating group members included, are added to allowed,
+// and its required fields are returned. The required fields of an optional component are only expected when one of its fields is present
+func definitionRequirements(dictionary *Dictionary, definition *Definition, present, allowed map[string]bool) (required []string, used bool) {
+	required = make([]string, 0)
+	if definition == nil {
+		return
+	}
+
+	for _, fieldID := range definition.Fields {
+		allowGroup(dictionary, fieldID, allowed)
+		used = used || present[fieldID]
+	}
+
+	required = append(required, definition.Required...)
+
+	for _, name := range definition.Components {
+		componentRequired, componentUsed := definitionRequirements(dictionary, dictionary.Components[name], present, allowed)
+		used = used || componentUsed
+		if _, contain := contains(definition.RequiredComponents, name); componentUsed || contain {
+			required = append(required, componentRequired...)
+		}
+	}
+
+	return
+}
+
+// allowGroup allow a field and, if it is a NUMINGROUP field, all the members of its repeating group
+func allowGroup(dictionary *Dictionary, fieldID string, allowed map[string]bool) {
+	if allowed[fieldID] {
+		return
+	}
+
+	allowed[fieldID] = true
+	if group := dictionary.Group(fieldID); group != nil {
+		for _, member := range group.Fields {
+			allowGroup(dictionary, member, allowed)
+		}
+	}
+}
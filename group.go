@@ -0,0 +1,68 @@
+package fixdecoder
+
+// DecodedGroup a repeating group. CountField is the NUMINGROUP field (e.g. 453 NoPartyIDs) introducing the group,
+// each instance holds the fields of one repetition in message order. A nested group appears in an instance as its own count field,
+// whose Group holds the nested instances
+type DecodedGroup struct {
+	CountField *DecodedField
+	Instances  [][]*DecodedField
+}
+
+// buildGroups link repeating groups to their count fields and return the top level groups
+func buildGroups(dictionary *Dictionary, dfs DecodedFields) []*DecodedGroup {
+	result := make([]*DecodedGroup, 0)
+
+	for i := 0; i < len(dfs); {
+		group, next := parseGroup(dictionary, dfs, i)
+		if group != nil {
+			result = append(result, group)
+		}
+		i = next
+	}
+
+	return result
+}
+
+// parseGroup parse the group introduced by dfs[i], if dfs[i] is a count field with a group definition.
+// It returns the group (nil if none) and the index of the first field after it
+func parseGroup(dictionary *Dictionary, dfs DecodedFields, i int) (*DecodedGroup, int) {
+	definition := dictionary.Group(dfs[i].FieldID)
+	if definition == nil || len(definition.Fields) == 0 {
+		return nil, i + 1
+	}
+
+	delimiter := definition.Fields[0]
+	members := make(map[string]bool, len(definition.Fields))
+	for _, member := range definition.Fields {
+		members[member] = true
+	}
+
+	group := &DecodedGroup{CountField: dfs[i], Instances: make([][]*DecodedField, 0)}
+	dfs[i].Group = group
+
+	var seen map[string]bool
+	j := i + 1
+	for j < len(dfs) {
+		fieldID := dfs[j].FieldID
+		if fieldID == delimiter {
+			// the delimiter starts a new instance
+			group.Instances = append(group.Instances, make([]*DecodedField, 0))
+			seen = make(map[string]bool)
+		} else if seen == nil || !members[fieldID] || seen[fieldID] {
+			// a field outside of the group, or repeated without a delimiter, ends the group
+			break
+		}
+
+		seen[fieldID] = true
+		last := len(group.Instances) - 1
+		group.Instances[last] = append(group.Instances[last], dfs[j])
+
+		if _, next := parseGroup(dictionary, dfs, j); next > j+1 {
+			j = next
+		} else {
+			j++
+		}
+	}
+
+	return group, j
+}
@@ -0,0 +1,52 @@
+package fixdecoder
+
+// Message a decoded FIX message
+type Message struct {
+	BeginString string
+	Raw         string // The message as decoded
+	Fields      DecodedFields
+	Groups      []*DecodedGroup // Top level repeating groups
+	Dictionary  *Dictionary     // Dictionary the message was decoded with
+	Offset      int             // Byte offset of the message within its stream, see StreamDecoder
+	Delimiter   string          // Field delimiter of the message, e.g. SOH or PIPE
+
+	lookup string // Lookup policy, see WithLookupPolicy
+}
+
+// Lookup policies of tags appearing more than once in a message, see WithLookupPolicy
+const (
+	LOOKUPFIRST = "first"
+	LOOKUPLAST  = "last"
+	LOOKUPERROR = "error"
+)
+
+// String decode to string
+func (m *Message) String() string {
+	return m.Fields.String()
+}
+
+// Lookup the field with the given tag, according to the lookup policy of the decoder: the first or last occurrence,
+// or an ErrAmbiguousField error if the tag appears more than once. ErrFieldNotFound if there is none
+func (m *Message) Lookup(tag int) (*DecodedField, error) {
+	var found *DecodedField
+	count := 0
+	for _, df := range m.Fields {
+		if df.Tag != tag {
+			continue
+		}
+
+		count++
+		if found == nil || m.lookup == LOOKUPLAST {
+			found = df
+		}
+	}
+
+	switch {
+	case found == nil:
+		return nil, &ErrFieldNotFound{Tag: tag}
+	case count > 1 && m.lookup == LOOKUPERROR:
+		return nil, &ErrAmbiguousField{Tag: tag, Count: count}
+	}
+
+	return found, nil
+}
@@ -0,0 +1,239 @@
+package fixdecoder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// terminalOrdStatuses OrdStatus <39> values after which an order does not trade anymore: filled, done for day, canceled, rejected and expired
+var terminalOrdStatuses = []string{"2", "3", "4", "8", "C"}
+
+// Order the lifecycle of an order, reconstructed from its messages. Quantities and prices are exact decimals
+type Order struct {
+	ClOrdIDs         []string // The ClOrdID <11> chain, from the original order to the last accepted replace or cancel
+	PendingClOrdIDs  []string // ClOrdID <11> of the replace and cancel requests neither accepted by an ExecutionReport <35=8> nor rejected yet
+	RejectedClOrdIDs []string // ClOrdID <11> of the replace and cancel requests rejected by an OrderCancelReject <35=9>
+	OrderID          string
+	Symbol           string
+	Side             string
+	OrderQty         Decimal
+	OrdStatus        string // Last OrdStatus <39>, empty until the first ExecutionReport or OrderCancelReject
+	CumQty           Decimal
+	LeavesQty        Decimal
+	AvgPx            Decimal
+	Events           []*OrderEvent
+	Issues           []*OrderIssue
+}
+
+// OrderEvent a message of the lifecycle of an order
+type OrderEvent struct {
+	Index            int    // Index of the message in the analyzed stream
+	MsgType          string // D, G, F, 8 or 9
+	ClOrdID          string
+	ExecType         string
+	OrdStatus        string
+	CumQty           string
+	LeavesQty        string
+	AvgPx            string
+	CxlRejResponseTo string // Of an OrderCancelReject: 1 for a cancel request, 2 for a replace request
+	CxlRejReason     string // Of an OrderCancelReject
+}
+
+// String the event, e.g. "8 ClOrdID=ORD1 ExecType=F OrdStatus=1 CumQty=50 LeavesQty=50 AvgPx=12.5"
+func (e *OrderEvent) String() string {
+	parts := []string{e.MsgType, "ClOrdID=" + e.ClOrdID}
+	for _, field := range []struct{ name, value string }{
+		{"ExecType", e.ExecType}, {"OrdStatus", e.OrdStatus}, {"CumQty", e.CumQty}, {"LeavesQty", e.LeavesQty}, {"AvgPx", e.AvgPx},
+		{"CxlRejResponseTo", e.CxlRejResponseTo}, {"CxlRejReason", e.CxlRejReason},
+	} {
+		if field.value != "" {
+			parts = append(parts, field.name+"="+field.value)
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// OrderIssue an inconsistency in the lifecycle of an order
+type OrderIssue struct {
+	Index   int // Index of the message in the analyzed stream
+	Message string
+}
+
+// OrderTracker reconstruct the lifecycle of orders from an ordered stream of NewOrderSingle <35=D>, OrderCancelReplaceRequest <35=G>,
+// OrderCancelRequest <35=F>, ExecutionReport <35=8> and OrderCancelReject <35=9> messages. Messages are chained into orders by
+// ClOrdID <11>, OrigClOrdID <41> and OrderID <37>, and inconsistencies are reported, such as CumQty <14> going backwards or fills after a terminal state
+type OrderTracker struct {
+	Orders    []*Order // In the order they were first seen
+	byClOrdID map[string]*Order
+	byOrderID map[string]*Order
+	count     int
+}
+
+// NewOrderTracker new order tracker instance
+func NewOrderTracker() *OrderTracker {
+	return &OrderTracker{byClOrdID: make(map[string]*Order), byOrderID: make(map[string]*Order)}
+}
+
+// Order the order a ClOrdID <11> belongs to, nil if unknown
+func (t *OrderTracker) Order(clOrdID string) *Order {
+	return t.byClOrdID[clOrdID]
+}
+
+// Add add the next message of the stream. Other messages are skipped
+func (t *OrderTracker) Add(dfs DecodedFields) {
+	index := t.count
+	t.count++
+
+	msgType := fieldValue(dfs, MSGTYPE)
+	switch msgType {
+	case "D", "G", "F", "8", "9":
+	default:
+		return
+	}
+
+	clOrdID, origClOrdID, orderID := fieldValue(dfs, "11"), fieldValue(dfs, "41"), fieldValue(dfs, "37")
+
+	order := t.find(clOrdID, origClOrdID, orderID)
+	if order == nil {
+		order = &Order{ClOrdIDs: make([]string, 0), PendingClOrdIDs: make([]string, 0), RejectedClOrdIDs: make([]string, 0), Events: make([]*OrderEvent, 0), Issues: make([]*OrderIssue, 0)}
+		t.Orders = append(t.Orders, order)
+
+		if msgType == "G" || msgType == "F" {
+			order.issue(index, "OrigClOrdID %s of an unknown order", origClOrdID)
+		}
+	} else if msgType == "D" {
+		order.issue(index, "NewOrderSingle with the ClOrdID %s of an existing order", clOrdID)
+	}
+
+	for _, id := range []string{origClOrdID, clOrdID} {
+		if id != "" && t.byClOrdID[id] == nil {
+			t.byClOrdID[id] = order
+			if id == clOrdID && (msgType == "G" || msgType == "F") {
+				// part of the chain only once accepted
+				order.PendingClOrdIDs = append(order.PendingClOrdIDs, id)
+			} else {
+				order.ClOrdIDs = append(order.ClOrdIDs, id)
+			}
+		}
+	}
+
+	if orderID != "" && orderID != "NONE" {
+		order.OrderID = orderID
+		t.byOrderID[orderID] = order
+	}
+
+	if symbol := fieldValue(dfs, "55"); symbol != "" {
+		order.Symbol = symbol
+	}
+	if side := fieldValue(dfs, "54"); side != "" {
+		order.Side = side
+	}
+	if orderQty, err := ParseDecimal(fieldValue(dfs, "38")); err == nil && (msgType == "D" || msgType == "8") {
+		// OrderQty of a replace request only applies once accepted
+		order.OrderQty = orderQty
+	}
+
+	event := &OrderEvent{Index: index, MsgType: msgType, ClOrdID: clOrdID}
+	order.Events = append(order.Events, event)
+
+	switch msgType {
+	case "8":
+		t.execution(order, event, dfs)
+	case "9":
+		t.cancelReject(order, event, dfs)
+	}
+}
+
+// find the order of a message, by ClOrdID <11>, OrigClOrdID <41> or OrderID <37>
+func (t *OrderTracker) find(clOrdID, origClOrdID, orderID string) *Order {
+	if order, found := t.byClOrdID[clOrdID]; found && clOrdID != "" {
+		return order
+	}
+
+	if order, found := t.byClOrdID[origClOrdID]; found && origClOrdID != "" {
+		return order
+	}
+
+	return t.byOrderID[orderID]
+}
+
+// execution apply an ExecutionReport to its order
+func (t *OrderTracker) execution(order *Order, event *OrderEvent, dfs DecodedFields) {
+	event.ExecType = fieldValue(dfs, "150")
+	event.OrdStatus = fieldValue(dfs, "39")
+	event.CumQty = fieldValue(dfs, "14")
+	event.LeavesQty = fieldValue(dfs, "151")
+	event.AvgPx = fieldValue(dfs, "6")
+
+	_, terminal := contains(terminalOrdStatuses, order.OrdStatus)
+	lastQty, _ := ParseDecimal(fieldValue(dfs, "32"))
+	correction := event.ExecType == "G" || event.ExecType == "H"
+	fill := !correction && (event.ExecType == "F" || event.ExecType == "1" || event.ExecType == "2" || lastQty.Sign() > 0)
+
+	if terminal && fill {
+		order.issue(event.Index, "fill after terminal OrdStatus %s", order.OrdStatus)
+	}
+
+	if cumQty, err := ParseDecimal(event.CumQty); err == nil {
+		if cumQty.Cmp(order.CumQty) < 0 && !correction {
+			order.issue(event.Index, "CumQty went backwards from %v to %v", order.CumQty, cumQty)
+		}
+		order.CumQty = cumQty
+	}
+
+	if leavesQty, err := ParseDecimal(event.LeavesQty); err == nil {
+		order.LeavesQty = leavesQty
+	}
+
+	if avgPx, err := ParseDecimal(event.AvgPx); err == nil {
+		order.AvgPx = avgPx
+	}
+
+	if event.OrdStatus != "" {
+		order.OrdStatus = event.OrdStatus
+	}
+
+	// accepted, unless rejected or still pending cancel or replace
+	if event.ExecType != "8" && event.ExecType != "6" && event.ExecType != "E" && order.resolve(event.ClOrdID) {
+		order.ClOrdIDs = append(order.ClOrdIDs, event.ClOrdID)
+	}
+
+	_, terminal = contains(terminalOrdStatuses, order.OrdStatus)
+	if !terminal && order.OrderQty.Sign() > 0 && event.CumQty != "" && event.LeavesQty != "" && order.CumQty.Add(order.LeavesQty).Cmp(order.OrderQty) != 0 {
+		order.issue(event.Index, "CumQty %v + LeavesQty %v differ from OrderQty %v", order.CumQty, order.LeavesQty, order.OrderQty)
+	}
+}
+
+// cancelReject apply an OrderCancelReject to its order: the ClOrdID of the rejected request is recorded, and the order keeps its OrdStatus <39>
+func (t *OrderTracker) cancelReject(order *Order, event *OrderEvent, dfs DecodedFields) {
+	event.OrdStatus = fieldValue(dfs, "39")
+	event.CxlRejResponseTo = fieldValue(dfs, "434")
+	event.CxlRejReason = fieldValue(dfs, "102")
+
+	if event.ClOrdID != "" {
+		order.resolve(event.ClOrdID)
+		order.RejectedClOrdIDs = append(order.RejectedClOrdIDs, event.ClOrdID)
+	}
+
+	if event.OrdStatus != "" {
+		order.OrdStatus = event.OrdStatus
+	}
+}
+
+// resolve remove a ClOrdID <11> from the pending requests, false if it was not pending
+func (o *Order) resolve(clOrdID string) bool {
+	for i, id := range o.PendingClOrdIDs {
+		if id == clOrdID {
+			o.PendingClOrdIDs = append(o.PendingClOrdIDs[:i], o.PendingClOrdIDs[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// issue report an inconsistency
+func (o *Order) issue(index int, format string, args ...interface{}) {
+	o.Issues = append(o.Issues, &OrderIssue{Index: index, Message: fmt.Sprintf(format, args...)})
+}
@@ -0,0 +1,115 @@
+package fixdecoder_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	fixdecoder "github.com/ilovelili/FixDecoder"
+)
+
+func TestOrderTracker(t *testing.T) {
+	messages := []string{
+		"35=D|11=ORD1|55=ACME|54=1|38=100|",
+		"35=8|11=ORD1|37=X1|150=0|39=0|14=0|151=100|6=0|",
+		"35=8|11=ORD1|37=X1|150=F|39=1|32=40|14=40|151=60|6=10|",
+		"35=G|11=ORD2|41=ORD1|38=80|",
+		"35=8|11=ORD2|41=ORD1|37=X1|150=5|39=5|38=80|14=40|151=40|6=10|",
+		"35=F|11=ORD3|41=ORD2|",
+		"35=9|11=ORD3|41=ORD2|37=X1|39=1|434=1|102=0|",
+		"35=8|37=X1|150=F|39=2|32=40|14=30|151=0|6=10|",
+		"35=8|11=ORD2|37=X1|150=F|39=2|32=10|14=90|151=0|6=10|",
+		"35=D|11=ORD9|55=ACME|54=2|38=0.3|",
+		"35=8|11=ORD9|37=X9|150=F|39=1|32=0.1|14=0.1|151=0.2|6=10|",
+		"35=0|",
+	}
+
+	tracker := fixdecoder.NewOrderTracker()
+	for _, message := range messages {
+		tracker.Add(fd.Decode("8=FIX.4.4|9=0|" + message + "10=000|"))
+	}
+
+	if len(tracker.Orders) != 2 {
+		t.Fatalf("expect 2 orders, actual %d", len(tracker.Orders))
+	}
+
+	order := tracker.Order("ORD3")
+	if order != tracker.Orders[0] || order != tracker.Order("ORD1") {
+		t.Fatalf("expect ORD1, ORD2 and ORD3 chained")
+	}
+
+	if ids := strings.Join(order.ClOrdIDs, ","); ids != "ORD1,ORD2" {
+		t.Errorf("expect ORD1,ORD2, actual %s", ids)
+	}
+
+	if len(order.PendingClOrdIDs) != 0 {
+		t.Errorf("expect no pending request, actual %v", order.PendingClOrdIDs)
+	}
+
+	if order.OrderID != "X1" || order.Symbol != "ACME" || order.OrderQty.String() != "80" || order.OrdStatus != "2" || order.CumQty.String() != "90" {
+		t.Errorf("expect filled order X1, actual %+v", order)
+	}
+
+	if len(order.Events) != 9 {
+		t.Errorf("expect 9 events, actual %d", len(order.Events))
+	}
+
+	expect := "8 ClOrdID=ORD1 ExecType=F OrdStatus=1 CumQty=40 LeavesQty=60 AvgPx=10"
+	if actual := order.Events[2].String(); actual != expect {
+		t.Errorf("expect %s, actual %s", expect, actual)
+	}
+
+	expect = "9 ClOrdID=ORD3 OrdStatus=1 CxlRejResponseTo=1 CxlRejReason=0"
+	if actual := order.Events[6].String(); actual != expect {
+		t.Errorf("expect %s, actual %s", expect, actual)
+	}
+
+	if ids := strings.Join(order.RejectedClOrdIDs, ","); ids != "ORD3" {
+		t.Errorf("expect ORD3 rejected, actual %s", ids)
+	}
+
+	issues := make([]string, 0)
+	for _, issue := range order.Issues {
+		issues = append(issues, fmt.Sprintf("%d %s", issue.Index, issue.Message))
+	}
+
+	expect = strings.Join([]string{
+		"7 CumQty went backwards from 40 to 30",
+		"8 fill after terminal OrdStatus 2",
+	}, "\n")
+
+	if actual := strings.Join(issues, "\n"); actual != expect {
+		t.Errorf("expect %s, actual %s", expect, actual)
+	}
+
+	// 0.1 + 0.2 is exactly 0.3
+	if issues := tracker.Orders[1].Issues; len(issues) != 0 {
+		t.Errorf("expect no issue for ORD9, actual %s", issues[0].Message)
+	}
+}
+
+func TestOrderTracker_Pending(t *testing.T) {
+	tracker := fixdecoder.NewOrderTracker()
+	for _, message := range []string{
+		"35=D|11=ORD1|55=ACME|54=1|38=100|",
+		"35=8|11=ORD1|37=X1|150=0|39=0|14=0|151=100|6=0|",
+		"35=G|11=ORD2|41=ORD1|38=80|",
+		"35=8|11=ORD2|41=ORD1|37=X1|150=E|39=E|14=0|151=100|6=0|",
+	} {
+		tracker.Add(fd.Decode("8=FIX.4.4|9=0|" + message + "10=000|"))
+	}
+
+	order := tracker.Order("ORD2")
+	if ids := strings.Join(order.ClOrdIDs, ","); ids != "ORD1" {
+		t.Errorf("expect ORD1, actual %s", ids)
+	}
+
+	if ids := strings.Join(order.PendingClOrdIDs, ","); ids != "ORD2" {
+		t.Errorf("expect ORD2 pending, actual %s", ids)
+	}
+
+	tracker.Add(fd.Decode("8=FIX.4.4|9=0|35=8|11=ORD2|41=ORD1|37=X1|150=5|39=5|38=80|14=0|151=80|6=0|10=000|"))
+	if ids := strings.Join(order.ClOrdIDs, ","); ids != "ORD1,ORD2" || len(order.PendingClOrdIDs) != 0 {
+		t.Errorf("expect ORD1,ORD2 accepted, actual %s pending %v", ids, order.PendingClOrdIDs)
+	}
+}
@@ -0,0 +1,628 @@
+package fixdecoder
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// pcap and pcapng magic numbers
+const (
+	pcapMagicMicro       = 0xa1b2c3d4
+	pcapMagicNano        = 0xa1b23c4d
+	pcapngSectionHeader  = 0x0a0d0d0a
+	pcapngByteOrderMagic = 0x1a2b3c4d
+)
+
+// pcapng block types
+const (
+	pcapngInterfaceDescription = 1
+	pcapngPacket               = 2
+	pcapngSimplePacket         = 3
+	pcapngEnhancedPacket       = 6
+)
+
+// link types, see https://www.tcpdump.org/linktypes.html
+const (
+	linkTypeNull      = 0
+	linkTypeEthernet  = 1
+	linkTypeRawIP     = 101
+	linkTypeLoop      = 108
+	linkTypeLinuxSLL  = 113
+	linkTypeLinuxSLL2 = 276
+	dltRaw            = 12
+	dltRawOpenBSD     = 14
+)
+
+// TCP flags
+const (
+	tcpFIN = 0x01
+	tcpSYN = 0x02
+	tcpRST = 0x04
+	tcpACK = 0x10
+)
+
+// maxPcapBlockSize packets and blocks larger than this are considered corrupted
+const maxPcapBlockSize = 16 * 1024 * 1024
+
+// ErrPcapFormat the capture is neither a pcap nor a pcapng file, or it is corrupted
+var ErrPcapFormat = errors.New("fixdecoder: invalid pcap or pcapng file")
+
+// CapturedMessage a FIX message read from a network capture
+type CapturedMessage struct {
+	Timestamp   time.Time // Capture time of the packet holding the end of the message
+	Source      string    // {{ip}}:{{port}}
+	Destination string    // {{ip}}:{{port}}
+	Direction   string    // OUTBOUND if sent by the side which opened the TCP connection, usually the FIX initiator, INBOUND otherwise
+	Message     *Message  // Offset is the byte offset of the message within its TCP stream
+}
+
+// PcapReader read the FIX messages of a pcap or pcapng capture file. TCP streams are reassembled per source and destination
+// (retransmitted and out of order segments included), and messages are framed across segments by BodyLength <9> and CheckSum <10>.
+// IPv4 and IPv6 over Ethernet, Linux cooked, loopback and raw IP captures are supported; fragmented IP packets are skipped
+type PcapReader struct {
+	decoder    *FixDecoder
+	reader     *bufio.Reader
+	order      binary.ByteOrder
+	pcapng     bool
+	linkType   uint32          // pcap only
+	resolution uint64          // pcap only, timestamp units per second
+	interfaces []pcapInterface // pcapng only, interfaces of the current section
+	flows      map[string]*tcpFlow
+	initiators map[string]string // Side which opened a connection, by connection
+	ready      []capturedResult
+	done       bool
+}
+
+// pcapInterface pcapng interface
+type pcapInterface struct {
+	linkType   uint32
+	resolution uint64 // Timestamp units per second
+}
+
+// capturedResult a message or an error, in capture order
+type capturedResult struct {
+	message *CapturedMessage
+	err     error
+}
+
+// NewPcapReader new pcap reader instance. The format, pcap or pcapng, is detected from the file header. Options are the ones of NewFixDecoder
+func NewPcapReader(r io.Reader, options ...Option) (*PcapReader, error) {
+	p := &PcapReader{
+		decoder:    NewFixDecoder(options...),
+		reader:     bufio.NewReaderSize(r, streamReadSize),
+		flows:      make(map[string]*tcpFlow),
+		initiators: make(map[string]string),
+	}
+
+	magic, err := p.reader.Peek(4)
+	if err != nil {
+		return nil, ErrPcapFormat
+	}
+
+	if binary.LittleEndian.Uint32(magic) == pcapngSectionHeader {
+		p.pcapng = true
+		return p, nil
+	}
+
+	header := make([]byte, 24)
+	if _, err := io.ReadFull(p.reader, header); err != nil {
+		return nil, ErrPcapFormat
+	}
+
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		switch order.Uint32(header) {
+		case pcapMagicMicro:
+			p.order, p.resolution = order, 1e6
+		case pcapMagicNano:
+			p.order, p.resolution = order, 1e9
+		}
+	}
+
+	if p.order == nil {
+		return nil, ErrPcapFormat
+	}
+
+	p.linkType = p.order.Uint32(header[20:]) & 0x0fffffff
+	return p, nil
+}
+
+// Next the next message of the capture. It returns io.EOF at the end of the capture, ErrPcapFormat if the capture is corrupted,
+// or the read error of the underlying reader. Any other error concerns one corrupted message, and Next can be called again
+func (p *PcapReader) Next() (*CapturedMessage, error) {
+	for len(p.ready) == 0 {
+		if p.done {
+			return nil, io.EOF
+		}
+
+		if err := p.readPacket(); err == io.EOF {
+			p.done = true
+
+			keys := make([]string, 0, len(p.flows))
+			for key := range p.flows {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			for _, key := range keys {
+				p.frame(p.flows[key], true)
+			}
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	result := p.ready[0]
+	p.ready = p.ready[1:]
+	return result.message, result.err
+}
+
+// readPacket read the next packet, or pcapng block, and reassemble its TCP segment if any
+func (p *PcapReader) readPacket() error {
+	if !p.pcapng {
+		header := make([]byte, 16)
+		if _, err := io.ReadFull(p.reader, header); err == io.EOF {
+			return io.EOF
+		} else if err != nil {
+			return ErrPcapFormat
+		}
+
+		length := p.order.Uint32(header[8:])
+		if length > maxPcapBlockSize {
+			return ErrPcapFormat
+		}
+
+		data := make([]byte, length)
+		if _, err := io.ReadFull(p.reader, data); err != nil {
+			return ErrPcapFormat
+		}
+
+		ticks := uint64(p.order.Uint32(header))*p.resolution + uint64(p.order.Uint32(header[4:]))
+		p.packet(p.linkType, ticksTime(ticks, p.resolution), data)
+		return nil
+	}
+
+	return p.readBlock()
+}
+
+// readBlock read the next pcapng block
+func (p *PcapReader) readBlock() error {
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(p.reader, header); err == io.EOF {
+		return io.EOF
+	} else if err != nil {
+		return ErrPcapFormat
+	}
+
+	if binary.LittleEndian.Uint32(header) == pcapngSectionHeader {
+		// the byte order of the section is given by its byte order magic
+		magic, err := p.reader.Peek(4)
+		if err != nil {
+			return ErrPcapFormat
+		}
+
+		p.order = binary.LittleEndian
+		if binary.BigEndian.Uint32(magic) == pcapngByteOrderMagic {
+			p.order = binary.BigEndian
+		} else if binary.LittleEndian.Uint32(magic) != pcapngByteOrderMagic {
+			return ErrPcapFormat
+		}
+		p.interfaces = nil
+	} else if p.order == nil {
+		return ErrPcapFormat
+	}
+
+	length := p.order.Uint32(header[4:])
+	if length < 12 || length%4 != 0 || length > maxPcapBlockSize {
+		return ErrPcapFormat
+	}
+
+	block := make([]byte, length-8)
+	if _, err := io.ReadFull(p.reader, block); err != nil {
+		return ErrPcapFormat
+	}
+	body := block[:len(block)-4]
+
+	switch p.order.Uint32(header) {
+	case pcapngInterfaceDescription:
+		if len(body) < 8 {
+			return ErrPcapFormat
+		}
+		p.interfaces = append(p.interfaces, pcapInterface{linkType: uint32(p.order.Uint16(body)), resolution: p.timestampResolution(body[8:])})
+	case pcapngEnhancedPacket, pcapngPacket:
+		if len(body) < 20 {
+			return ErrPcapFormat
+		}
+
+		id := p.order.Uint32(body)
+		if p.order.Uint32(header) == pcapngPacket {
+			id = uint32(p.order.Uint16(body))
+		}
+
+		length := p.order.Uint32(body[12:])
+		if id >= uint32(len(p.interfaces)) || uint64(length) > uint64(len(body)-20) {
+			return ErrPcapFormat
+		}
+
+		iface := p.interfaces[id]
+		ticks := uint64(p.order.Uint32(body[4:]))<<32 | uint64(p.order.Uint32(body[8:]))
+		p.packet(iface.linkType, ticksTime(ticks, iface.resolution), body[20:20+length])
+	case pcapngSimplePacket:
+		if len(body) < 4 || len(p.interfaces) == 0 {
+			return ErrPcapFormat
+		}
+
+		data := body[4:]
+		if length := p.order.Uint32(body); uint64(length) < uint64(len(data)) {
+			data = data[:length]
+		}
+		p.packet(p.interfaces[0].linkType, time.Time{}, data)
+	}
+
+	return nil
+}
+
+// timestampResolution the timestamp units per second of a pcapng interface, from its if_tsresol option. Microseconds by default
+func (p *PcapReader) timestampResolution(options []byte) uint64 {
+	for len(options) >= 4 {
+		code, length := p.order.Uint16(options), int(p.order.Uint16(options[2:]))
+		if code == 0 || 4+length > len(options) {
+			break
+		}
+
+		if code == 9 && length == 1 {
+			exponent, resolution := options[4], uint64(1)
+			for i := 0; i < int(exponent&0x7f) && resolution < 1e18; i++ {
+				if exponent&0x80 != 0 {
+					resolution *= 2
+				} else {
+					resolution *= 10
+				}
+			}
+			return resolution
+		}
+
+		options = options[4+(length+3)/4*4:]
+	}
+
+	return 1e6
+}
+
+// ticksTime the time of a capture timestamp, in units of 1/resolution seconds since the epoch
+func ticksTime(ticks, resolution uint64) time.Time {
+	nanoseconds := ticks % resolution
+	if resolution > 1e9 {
+		nanoseconds /= resolution / 1e9
+	} else {
+		nanoseconds = nanoseconds * 1e9 / resolution
+	}
+
+	return time.Unix(int64(ticks/resolution), int64(nanoseconds))
+}
+
+// packet reassemble the TCP segment of a packet, if any
+func (p *PcapReader) packet(linkType uint32, timestamp time.Time, data []byte) {
+	ip := linkPayload(linkType, data)
+	if ip == nil {
+		return
+	}
+
+	source, destination, segment := ipPayload(ip)
+	if segment == nil || len(segment) < 20 {
+		return
+	}
+
+	offset := int(segment[12]>>4) * 4
+	if offset < 20 || offset > len(segment) {
+		return
+	}
+
+	source = net.JoinHostPort(source, strconv.Itoa(int(binary.BigEndian.Uint16(segment))))
+	destination = net.JoinHostPort(destination, strconv.Itoa(int(binary.BigEndian.Uint16(segment[2:]))))
+	seq, flags := binary.BigEndian.Uint32(segment[4:]), segment[13]
+
+	connection := connectionKey(source, destination)
+	if flags&tcpSYN != 0 && flags&tcpACK == 0 {
+		p.initiators[connection] = source
+	}
+
+	key := source + ">" + destination
+	flow, found := p.flows[key]
+	if !found || (flags&tcpSYN != 0 && seq+1 != flow.next) {
+		// new connection, or a new one reusing the same ports
+		if found {
+			p.frame(flow, true)
+		}
+		flow = &tcpFlow{source: source, destination: destination, connection: connection, pending: make(map[uint32][]byte)}
+		p.flows[key] = flow
+	}
+
+	flow.timestamp = timestamp
+	flow.add(seq, flags, segment[offset:])
+	p.frame(flow, flags&(tcpFIN|tcpRST) != 0)
+}
+
+// frame decode the messages reassembled so far. final tells no more data will come
+func (p *PcapReader) frame(flow *tcpFlow, final bool) {
+	if final {
+		flow.skipGaps()
+	}
+
+	for {
+		start := bytes.Index(flow.buffer, beginStringPrefix)
+		if start < 0 {
+			// keep what may be the beginning of the prefix
+			if keep := len(beginStringPrefix) - 1; len(flow.buffer) > keep {
+				flow.discard(len(flow.buffer) - keep)
+			}
+			return
+		}
+
+		flow.discard(start)
+		end, err := frameMessage(flow.buffer, p.decoder.delimiter, final || len(flow.buffer) >= maxMessageSize)
+		if err == errNeedMore {
+			return
+		}
+
+		offset := flow.offset
+		if err != nil {
+			// skip this BeginString to resynchronize on the next one
+			flow.discard(1)
+			p.ready = append(p.ready, capturedResult{err: withOffset(err, offset)})
+			continue
+		}
+
+		raw := string(flow.buffer[:end])
+		flow.discard(end)
+
+		msg, err := p.decoder.DecodeE(raw)
+		if err != nil {
+			p.ready = append(p.ready, capturedResult{err: withOffset(err, offset)})
+			continue
+		}
+
+		msg.Offset = offset
+		p.ready = append(p.ready, capturedResult{message: &CapturedMessage{
+			Timestamp:   flow.timestamp,
+			Source:      flow.source,
+			Destination: flow.destination,
+			Direction:   p.direction(flow),
+			Message:     msg,
+		}})
+	}
+}
+
+// direction OUTBOUND if the flow comes from the side which opened the connection. Without its SYN in the capture,
+// the side with the highest port, usually an ephemeral one, is deemed to have opened it
+func (p *PcapReader) direction(flow *tcpFlow) string {
+	initiator, found := p.initiators[flow.connection]
+	if !found {
+		_, sourcePort, _ := net.SplitHostPort(flow.source)
+		_, destinationPort, _ := net.SplitHostPort(flow.destination)
+		source, _ := strconv.Atoi(sourcePort)
+		destination, _ := strconv.Atoi(destinationPort)
+
+		if source > destination {
+			return OUTBOUND
+		}
+		return INBOUND
+	}
+
+	if initiator == flow.source {
+		return OUTBOUND
+	}
+
+	return INBOUND
+}
+
+// linkPayload the IP packet of a link layer frame, nil if it does not hold one
+func linkPayload(linkType uint32, data []byte) []byte {
+	switch linkType {
+	case linkTypeEthernet:
+		if len(data) < 14 {
+			return nil
+		}
+
+		etherType, p := binary.BigEndian.Uint16(data[12:]), 14
+		for (etherType == 0x8100 || etherType == 0x88a8) && len(data) >= p+4 {
+			// VLAN tags
+			etherType, p = binary.BigEndian.Uint16(data[p+2:]), p+4
+		}
+
+		if etherType != 0x0800 && etherType != 0x86dd {
+			return nil
+		}
+		return data[p:]
+	case linkTypeNull, linkTypeLoop:
+		if len(data) < 4 {
+			return nil
+		}
+		return data[4:]
+	case linkTypeRawIP, dltRaw, dltRawOpenBSD:
+		return data
+	case linkTypeLinuxSLL:
+		if len(data) < 16 {
+			return nil
+		}
+		return data[16:]
+	case linkTypeLinuxSLL2:
+		if len(data) < 20 {
+			return nil
+		}
+		return data[20:]
+	}
+
+	return nil
+}
+
+// ipPayload the addresses and TCP segment of an IPv4 or IPv6 packet, nil if it does not hold one
+func ipPayload(data []byte) (string, string, []byte) {
+	if len(data) == 0 {
+		return "", "", nil
+	}
+
+	switch data[0] >> 4 {
+	case 4:
+		if len(data) < 20 {
+			return "", "", nil
+		}
+
+		length, total := int(data[0]&0x0f)*4, int(binary.BigEndian.Uint16(data[2:]))
+		fragmented := binary.BigEndian.Uint16(data[6:])&0x3fff != 0
+		if data[9] != 6 || fragmented || length < 20 || total < length || total > len(data) {
+			return "", "", nil
+		}
+
+		return net.IP(data[12:16]).String(), net.IP(data[16:20]).String(), data[length:total]
+	case 6:
+		if len(data) < 40 {
+			return "", "", nil
+		}
+
+		next, p := data[6], 40
+		total := p + int(binary.BigEndian.Uint16(data[4:]))
+		if total > len(data) {
+			return "", "", nil
+		}
+
+		// hop-by-hop, routing and destination options extension headers
+		for (next == 0 || next == 43 || next == 60) && p+8 <= total {
+			next, p = data[p], p+(int(data[p+1])+1)*8
+		}
+
+		if next != 6 || p > total {
+			return "", "", nil
+		}
+
+		return net.IP(data[8:24]).String(), net.IP(data[24:40]).String(), data[p:total]
+	}
+
+	return "", "", nil
+}
+
+// connectionKey the key of a TCP connection, the same for both of its flows
+func connectionKey(a, b string) string {
+	if a > b {
+		a, b = b, a
+	}
+
+	return a + "<>" + b
+}
+
+// tcpFlow one direction of a TCP connection, reassembled
+type tcpFlow struct {
+	source      string
+	destination string
+	connection  string
+	timestamp   time.Time // Of the last packet
+	started     bool
+	next        uint32            // Next expected sequence number
+	pending     map[uint32][]byte // Out of order segments, by sequence number
+	pendingSize int
+	buffer      []byte
+	offset      int         // Stream offset of buffer[0]
+	gaps        []streamGap // Data missing from the capture, by position in buffer
+}
+
+// streamGap size bytes of a TCP stream missing from the capture right before buffer[index]
+type streamGap struct {
+	index int
+	size  int
+}
+
+// add add a segment to the stream. Retransmitted data is dropped, and out of order segments are kept until the gap before them is filled
+func (f *tcpFlow) add(seq uint32, flags byte, payload []byte) {
+	if flags&tcpSYN != 0 {
+		f.next, f.started = seq+1, true
+		seq++
+	}
+
+	if len(payload) == 0 {
+		return
+	}
+
+	if !f.started {
+		f.next, f.started = seq, true
+	}
+
+	if int32(seq-f.next) > 0 {
+		if previous, found := f.pending[seq]; !found || len(previous) < len(payload) {
+			f.pendingSize += len(payload) - len(previous)
+			f.pending[seq] = append([]byte(nil), payload...)
+		}
+
+		if f.pendingSize > maxMessageSize {
+			// the missing data is not in the capture
+			f.skipGaps()
+		}
+		return
+	}
+
+	f.append(seq, payload)
+	f.drain()
+}
+
+// append append the part of a segment after the next expected sequence number
+func (f *tcpFlow) append(seq uint32, payload []byte) {
+	if overlap := int(f.next - seq); overlap < len(payload) {
+		f.buffer = append(f.buffer, payload[overlap:]...)
+		f.next = seq + uint32(len(payload))
+	}
+}
+
+// drain append the pending segments which now follow the stream
+func (f *tcpFlow) drain() {
+	for appended := true; appended; {
+		appended = false
+		for seq, payload := range f.pending {
+			if int32(seq-f.next) <= 0 {
+				f.append(seq, payload)
+				f.pendingSize -= len(payload)
+				delete(f.pending, seq)
+				appended = true
+			}
+		}
+	}
+}
+
+// skipGaps append all the pending segments, skipping the data missing before them
+func (f *tcpFlow) skipGaps() {
+	for len(f.pending) > 0 {
+		first := true
+		var next uint32
+		for seq := range f.pending {
+			if first || int32(seq-next) < 0 {
+				next, first = seq, false
+			}
+		}
+
+		if skipped := int(int32(next - f.next)); skipped > 0 {
+			f.gaps = append(f.gaps, streamGap{index: len(f.buffer), size: skipped})
+		}
+
+		f.next = next
+		f.drain()
+	}
+}
+
+// discard drop the first n bytes of the buffer, and the data missing before them
+func (f *tcpFlow) discard(n int) {
+	f.offset += n
+	gaps := f.gaps[:0]
+	for _, gap := range f.gaps {
+		if gap.index <= n {
+			f.offset += gap.size
+		} else {
+			gaps = append(gaps, streamGap{index: gap.index - n, size: gap.size})
+		}
+	}
+
+	f.gaps = gaps
+	f.buffer = f.buffer[:copy(f.buffer, f.buffer[n:])]
+}
@@ -0,0 +1,192 @@
+package fixdecoder_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	fixdecoder "github.com/ilovelili/FixDecoder"
+)
+
+// capturedPacket a TCP segment of a test capture
+type capturedPacket struct {
+	time    int64 // microseconds
+	client  bool
+	seq     uint32
+	flags   byte
+	payload string
+}
+
+func TestPcapReader(t *testing.T) {
+	logon := fixdecoder.NewMessageBuilder("FIX.4.4", "A").SetField("49", "CLIENT").SetField("56", "BROKER").SetField("34", "1").String()
+	reply := fixdecoder.NewMessageBuilder("FIX.4.4", "A").SetField("49", "BROKER").SetField("56", "CLIENT").SetField("34", "1").String()
+	heartbeat := fixdecoder.NewMessageBuilder("FIX.4.4", "0").SetField("49", "BROKER").SetField("56", "CLIENT").SetField("34", "2").String()
+
+	packets := []capturedPacket{
+		{time: 1, client: true, seq: 1000, flags: 0x02},
+		{time: 2, client: false, seq: 5000, flags: 0x12},
+		// out of order, then retransmitted
+		{time: 3, client: true, seq: 1001 + 20, flags: 0x18, payload: logon[20:]},
+		{time: 4, client: true, seq: 1001, flags: 0x18, payload: logon[:20]},
+		{time: 5, client: true, seq: 1001, flags: 0x18, payload: logon[:20]},
+		// two messages in one segment
+		{time: 6, client: false, seq: 5001, flags: 0x18, payload: reply + heartbeat},
+	}
+
+	captures := map[string][]byte{
+		"pcap":   pcapFile(packets),
+		"pcapng": pcapngFile(packets),
+	}
+
+	for format, capture := range captures {
+		r, err := fixdecoder.NewPcapReader(bytes.NewReader(capture))
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+
+		actual := make([]string, 0)
+		for {
+			m, err := r.Next()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("%s: %v", format, err)
+			}
+
+			actual = append(actual, strings.Join([]string{
+				m.Timestamp.UTC().Format("05.000000"), m.Source, m.Destination, m.Direction, m.Message.Fields[2].Value, m.Message.Fields[5].Value,
+			}, " "))
+		}
+
+		source, destination := pcapAddress(format, true), pcapAddress(format, false)
+		expect := strings.Join([]string{
+			"00.000004 " + source + " " + destination + " OUT A 1",
+			"00.000006 " + destination + " " + source + " IN A 1",
+			"00.000006 " + destination + " " + source + " IN 0 2",
+		}, ",")
+
+		if strings.Join(actual, ",") != expect {
+			t.Errorf("%s: expect %s, actual %s", format, expect, strings.Join(actual, ","))
+		}
+	}
+
+	if _, err := fixdecoder.NewPcapReader(strings.NewReader(validfixmessage)); err != fixdecoder.ErrPcapFormat {
+		t.Errorf("expect %v, actual %v", fixdecoder.ErrPcapFormat, err)
+	}
+}
+
+func TestPcapReader_Gap(t *testing.T) {
+	logon := fixdecoder.NewMessageBuilder("FIX.4.4", "A").SetField("49", "CLIENT").SetField("56", "BROKER").SetField("34", "1").String()
+	heartbeat := fixdecoder.NewMessageBuilder("FIX.4.4", "0").SetField("49", "CLIENT").SetField("56", "BROKER").SetField("34", "3").String()
+
+	// 100 bytes of the stream are missing from the capture
+	r, err := fixdecoder.NewPcapReader(bytes.NewReader(pcapFile([]capturedPacket{
+		{time: 1, client: true, seq: 1000, flags: 0x02},
+		{time: 2, client: true, seq: 1001, flags: 0x18, payload: logon},
+		{time: 3, client: true, seq: 1001 + uint32(len(logon)) + 100, flags: 0x18, payload: heartbeat},
+	})))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	offsets := make([]int, 0)
+	for {
+		m, err := r.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		offsets = append(offsets, m.Message.Offset)
+	}
+
+	if len(offsets) != 2 || offsets[0] != 0 || offsets[1] != len(logon)+100 {
+		t.Errorf("expect offsets 0 and %d, actual %v", len(logon)+100, offsets)
+	}
+}
+
+// pcapAddress the address of the client or the server: IPv4 in pcap captures, IPv6 in pcapng ones
+func pcapAddress(format string, client bool) string {
+	host, port := "10.0.0.2", "9876"
+	if client {
+		host, port = "10.0.0.1", "50000"
+	}
+
+	if format == "pcapng" {
+		host = "fd00::" + host[len(host)-1:]
+	}
+
+	return net.JoinHostPort(host, port)
+}
+
+// pcapFile a little endian pcap capture of IPv4 packets over Ethernet
+func pcapFile(packets []capturedPacket) []byte {
+	buffer := &bytes.Buffer{}
+	binary.Write(buffer, binary.LittleEndian, []uint32{0xa1b2c3d4, 0x00040002, 0, 0, 65535, 1})
+
+	for _, packet := range packets {
+		frame := ethernetFrame(packet, false)
+		binary.Write(buffer, binary.LittleEndian, []uint32{uint32(packet.time / 1e6), uint32(packet.time % 1e6), uint32(len(frame)), uint32(len(frame))})
+		buffer.Write(frame)
+	}
+
+	return buffer.Bytes()
+}
+
+// pcapngFile a big endian pcapng capture of IPv6 packets over Ethernet
+func pcapngFile(packets []capturedPacket) []byte {
+	buffer := &bytes.Buffer{}
+	binary.Write(buffer, binary.BigEndian, []uint32{0x0a0d0d0a, 28, 0x1a2b3c4d, 0x00010000, 0xffffffff, 0xffffffff, 28})
+	binary.Write(buffer, binary.BigEndian, []uint32{1, 20, 0x00010000, 65535, 20})
+
+	for _, packet := range packets {
+		frame := ethernetFrame(packet, true)
+		padded := (len(frame) + 3) / 4 * 4
+		binary.Write(buffer, binary.BigEndian, []uint32{6, uint32(32 + padded), 0, uint32(packet.time >> 32), uint32(packet.time), uint32(len(frame)), uint32(len(frame))})
+		buffer.Write(frame)
+		buffer.Write(make([]byte, padded-len(frame)))
+		binary.Write(buffer, binary.BigEndian, uint32(32+padded))
+	}
+
+	return buffer.Bytes()
+}
+
+// ethernetFrame an Ethernet frame holding a TCP segment between the client and the server
+func ethernetFrame(packet capturedPacket, ipv6 bool) []byte {
+	sourcePort, destinationPort := uint16(9876), uint16(50000)
+	sourceHost, destinationHost := byte(2), byte(1)
+	if packet.client {
+		sourcePort, destinationPort, sourceHost, destinationHost = destinationPort, sourcePort, destinationHost, sourceHost
+	}
+
+	segment := make([]byte, 20, 20+len(packet.payload))
+	binary.BigEndian.PutUint16(segment, sourcePort)
+	binary.BigEndian.PutUint16(segment[2:], destinationPort)
+	binary.BigEndian.PutUint32(segment[4:], packet.seq)
+	segment[12], segment[13] = 5<<4, packet.flags
+	segment = append(segment, packet.payload...)
+
+	frame := make([]byte, 14)
+	if ipv6 {
+		binary.BigEndian.PutUint16(frame[12:], 0x86dd)
+		header := make([]byte, 40)
+		header[0], header[6] = 6<<4, 6
+		binary.BigEndian.PutUint16(header[4:], uint16(len(segment)))
+		copy(header[8:], net.ParseIP("fd00::"+string('0'+sourceHost)))
+		copy(header[24:], net.ParseIP("fd00::"+string('0'+destinationHost)))
+		frame = append(frame, header...)
+	} else {
+		binary.BigEndian.PutUint16(frame[12:], 0x0800)
+		header := make([]byte, 20)
+		header[0], header[9] = 4<<4|5, 6
+		binary.BigEndian.PutUint16(header[2:], uint16(20+len(segment)))
+		copy(header[12:], []byte{10, 0, 0, sourceHost})
+		copy(header[16:], []byte{10, 0, 0, destinationHost})
+		frame = append(frame, header...)
+	}
+
+	return append(frame, segment...)
+}
@@ -9,6 +9,33 @@ func Fields() string {
 	return gjson.Get(fix, "fieldsByTag").String()
 }
 
+// Groups get repeating group definitions group by the tag of their NUMINGROUP count field.
+// The first field of a group is its delimiter: it starts every instance of the group
+func Groups() string {
+	return gjson.Get(fix, "groupsByTag").String()
+}
+
+// Header get the standard header definition: its fields in order, and the required ones
+func Header() string {
+	return gjson.Get(fix, "header").String()
+}
+
+// Trailer get the standard trailer definition
+func Trailer() string {
+	return gjson.Get(fix, "trailer").String()
+}
+
+// Components get component definitions group by component name
+func Components() string {
+	return gjson.Get(fix, "componentsByName").String()
+}
+
+// Messages get message definitions group by MsgType <35>. Each definition lists its body fields in order, the required ones,
+// and the components it contains. Header and trailer fields are shared by all messages
+func Messages() string {
+	return gjson.Get(fix, "messagesByType").String()
+}
+
 // SystemFieldIDs get system fields IDs (like checksum)
 func SystemFieldIDs() []string {
 	result := make([]string, 0)
@@ -24,6 +51,343 @@ func SystemFieldIDs() []string {
 const fix = `
 {
 	"systemFieldIds": [10],
+	"groupsByTag": {
+		"33": {
+			"fields": [58, 354, 355]
+		},
+		"73": {
+			"fields": [11, 526, 67, 583, 160, 453, 229, 75, 1, 660, 581, 589, 590, 591, 78, 63, 64, 544, 635, 21, 18, 110, 111, 100, 386, 81, 55, 65, 48, 22, 454, 460, 461, 167, 762, 200, 541, 224, 225, 239, 226, 227, 228, 255, 543, 470, 471, 472, 240, 202, 947, 206, 231, 223, 207, 106, 348, 349, 107, 350, 351, 691, 667, 875, 876, 873, 874, 864, 711, 140, 54, 401, 114, 60, 232, 38, 152, 516, 468, 469, 40, 423, 44, 99, 15, 376, 377, 23, 117, 59, 168, 432, 126, 427, 528, 529, 582, 121, 120, 775, 58, 354, 355, 193, 192, 640, 77, 203, 210, 211, 388, 389, 494, 14, 39, 636, 151, 84, 6, 103]
+		},
+		"78": {
+			"fields": [79, 661, 573, 366, 80, 467, 81, 539, 208, 209, 161, 360, 361, 12, 13, 479, 497, 153, 154, 119, 737, 120, 736, 155, 156, 742, 741, 136, 576, 635, 780, 172, 169, 170, 171, 85]
+		},
+		"85": {
+			"fields": [165, 787, 781]
+		},
+		"124": {
+			"fields": [32, 17, 527, 31, 669, 29]
+		},
+		"136": {
+			"fields": [137, 138, 139, 891]
+		},
+		"146": {
+			"fields": [55, 65, 48, 22, 454, 460, 461, 167, 762, 200, 541, 224, 225, 239, 226, 227, 228, 255, 543, 470, 471, 472, 240, 202, 947, 206, 231, 223, 207, 106, 348, 349, 107, 350, 351, 691, 667, 875, 876, 873, 874, 864, 913, 914, 915, 918, 788, 916, 917, 919, 898, 711, 555, 140, 303, 537, 336, 625, 229, 54, 854, 38, 152, 516, 468, 469, 63, 64, 193, 192, 15, 232, 1, 660, 581, 735, 692, 40, 62, 126, 60, 423, 44, 640, 218, 220, 221, 222, 662, 663, 699, 761, 453, 561, 562, 827, 668, 869, 870, 292, 58, 354, 355]
+		},
+		"199": {
+			"fields": [104]
+		},
+		"215": {
+			"fields": [216, 217]
+		},
+		"232": {
+			"fields": [233, 234]
+		},
+		"267": {
+			"fields": [269]
+		},
+		"268": {
+			"fields": [279, 285, 269, 278, 280, 55, 65, 48, 22, 454, 460, 461, 167, 762, 200, 541, 224, 225, 239, 226, 227, 228, 255, 543, 470, 471, 472, 240, 202, 947, 206, 231, 223, 207, 106, 348, 349, 107, 350, 351, 691, 667, 875, 876, 873, 874, 864, 913, 914, 915, 918, 788, 916, 917, 919, 898, 711, 555, 291, 292, 270, 15, 271, 272, 273, 274, 275, 336, 625, 276, 277, 282, 283, 284, 286, 59, 432, 126, 110, 18, 287, 37, 299, 288, 289, 346, 290, 546, 811, 451, 58, 354, 355]
+		},
+		"295": {
+			"fields": [299, 55, 65, 48, 22, 454, 460, 461, 167, 762, 200, 541, 224, 225, 239, 226, 227, 228, 255, 543, 470, 471, 472, 240, 202, 947, 206, 231, 223, 207, 106, 348, 349, 107, 350, 351, 691, 667, 875, 876, 873, 874, 864, 913, 914, 915, 918, 788, 916, 917, 919, 898, 711, 555, 132, 133, 134, 135, 62, 188, 190, 189, 191, 631, 632, 633, 634, 60, 336, 625, 64, 40, 193, 192, 642, 643, 15, 368]
+		},
+		"296": {
+			"fields": [302, 311, 312, 309, 305, 457, 462, 463, 310, 763, 313, 542, 241, 242, 243, 244, 245, 246, 256, 595, 592, 593, 594, 247, 316, 941, 317, 436, 435, 308, 306, 362, 363, 307, 364, 365, 877, 878, 318, 879, 810, 882, 883, 884, 885, 886, 887, 367, 304, 893, 295]
+		},
+		"382": {
+			"fields": [375, 337, 437, 438, 655]
+		},
+		"384": {
+			"fields": [372, 385]
+		},
+		"386": {
+			"fields": [336, 625]
+		},
+		"398": {
+			"fields": [399, 400, 401, 404, 441, 402, 403, 405, 406, 407, 408]
+		},
+		"420": {
+			"fields": [66, 421, 54, 336, 625, 430, 63, 64, 1, 660, 12, 13, 479, 497, 44, 423, 406, 58, 354, 355]
+		},
+		"428": {
+			"fields": [55, 65, 48, 22, 454, 460, 461, 167, 762, 200, 541, 224, 225, 239, 226, 227, 228, 255, 543, 470, 471, 472, 240, 202, 947, 206, 231, 223, 207, 106, 348, 349, 107, 350, 351, 691, 667, 875, 876, 873, 874, 864, 711, 140, 11, 526, 54, 44, 15, 58, 354, 355]
+		},
+		"453": {
+			"fields": [448, 447, 452, 802]
+		},
+		"454": {
+			"fields": [455, 456]
+		},
+		"457": {
+			"fields": [458, 459]
+		},
+		"473": {
+			"fields": [509, 511, 474, 482, 539, 522, 486, 475]
+		},
+		"510": {
+			"fields": [477, 512, 478, 498, 499, 500, 501, 502]
+		},
+		"518": {
+			"fields": [519, 520, 521]
+		},
+		"539": {
+			"fields": [524, 525, 538, 804]
+		},
+		"552": {
+			"fields": [54, 37, 198, 11, 526, 583, 586, 453, 229, 75, 1, 660, 581, 81, 575, 576, 635, 578, 579, 376, 377, 582, 121, 120, 775, 58, 354, 355, 157, 230, 158, 159, 738, 920, 921, 922, 238, 237, 118, 119, 155, 156, 77, 752, 518, 136, 825, 826, 591, 70, 78, 854, 38, 152, 516, 468, 469, 40, 423, 44, 99, 15, 59, 168, 432, 126, 427, 528, 529, 18, 544]
+		},
+		"555": {
+			"fields": [600, 601, 602, 603, 604, 605, 606, 607, 608, 609, 764, 610, 611, 248, 249, 250, 251, 252, 253, 257, 599, 596, 597, 598, 254, 612, 942, 613, 614, 615, 616, 617, 618, 619, 620, 621, 622, 623, 624, 556, 740, 739, 955, 956, 687, 690, 683, 670, 564, 565, 654, 566, 587, 588, 675, 685, 539, 1, 660, 581, 637, 627]
+		},
+		"558": {
+			"fields": [167, 762, 460, 461]
+		},
+		"627": {
+			"fields": [628, 629, 630]
+		},
+		"670": {
+			"fields": [671, 672, 673, 674, 675]
+		},
+		"683": {
+			"fields": [688, 689]
+		},
+		"702": {
+			"fields": [703, 704, 705, 706, 539]
+		},
+		"711": {
+			"fields": [311, 312, 309, 305, 457, 462, 463, 310, 763, 313, 542, 241, 242, 243, 244, 245, 246, 256, 595, 592, 593, 594, 247, 316, 941, 317, 436, 435, 308, 306, 362, 363, 307, 364, 365, 877, 878, 318, 879, 810, 882, 883, 884, 885, 886, 887]
+		},
+		"735": {
+			"fields": [695]
+		},
+		"753": {
+			"fields": [707, 708]
+		},
+		"756": {
+			"fields": [757, 758, 759, 806]
+		},
+		"768": {
+			"fields": [769, 770, 771]
+		},
+		"778": {
+			"fields": [162, 163, 214, 453, 54, 460, 167, 461, 168, 126, 779, 172, 169, 170, 171, 492, 476, 488, 489, 503, 490, 491, 504, 505, 85]
+		},
+		"781": {
+			"fields": [782, 783, 784, 801]
+		},
+		"801": {
+			"fields": [785, 786]
+		},
+		"802": {
+			"fields": [523, 803]
+		},
+		"804": {
+			"fields": [545, 805]
+		},
+		"806": {
+			"fields": [760, 807]
+		},
+		"816": {
+			"fields": [817]
+		},
+		"862": {
+			"fields": [528, 529, 863]
+		},
+		"864": {
+			"fields": [865, 866, 867, 868]
+		},
+		"870": {
+			"fields": [871, 872]
+		},
+		"887": {
+			"fields": [888, 889]
+		},
+		"897": {
+			"fields": [571, 818]
+		},
+		"936": {
+			"fields": [930, 931, 283, 284, 928, 929]
+		},
+		"938": {
+			"fields": [896]
+		},
+		"948": {
+			"fields": [949, 950, 951, 952]
+		},
+		"952": {
+			"fields": [953, 954]
+		}
+	},
+	"header": {
+		"fields": [8, 9, 35, 49, 56, 115, 128, 90, 91, 34, 50, 142, 57, 143, 116, 144, 129, 145, 43, 97, 52, 122, 212, 213, 347, 369, 627],
+		"required": [8, 9, 35, 49, 56, 34, 52]
+	},
+	"trailer": {
+		"fields": [93, 89, 10],
+		"required": [10]
+	},
+	"componentsByName": {
+		"Parties": {
+			"fields": [453],
+			"required": []
+		},
+		"Instrument": {
+			"fields": [55, 65, 48, 22, 454, 460, 461, 167, 762, 200, 541, 224, 225, 239, 226, 227, 228, 255, 543, 470, 471, 472, 240, 202, 947, 206, 231, 223, 207, 106, 348, 349, 107, 350, 351, 691, 667, 875, 876, 873, 874, 864],
+			"required": [55]
+		},
+		"FinancingDetails": {
+			"fields": [913, 914, 915, 918, 788, 916, 917, 919, 898],
+			"required": []
+		},
+		"CommissionData": {
+			"fields": [12, 13, 479, 497],
+			"required": []
+		},
+		"Stipulations": {
+			"fields": [232],
+			"required": []
+		},
+		"SpreadOrBenchmarkCurveData": {
+			"fields": [218, 220, 221, 222, 662, 663, 699, 761],
+			"required": []
+		},
+		"YieldData": {
+			"fields": [235, 236, 701, 696, 697, 698],
+			"required": []
+		},
+		"PegInstructions": {
+			"fields": [211, 835, 836, 837, 838, 840],
+			"required": []
+		},
+		"DiscretionInstructions": {
+			"fields": [388, 389, 841, 842, 843, 844, 846],
+			"required": []
+		},
+		"TrdRegTimestamps": {
+			"fields": [768],
+			"required": []
+		},
+		"MiscFees": {
+			"fields": [136],
+			"required": []
+		}
+	},
+	"messagesByType": {
+		"0": {
+			"name": "Heartbeat",
+			"fields": [112],
+			"required": []
+		},
+		"1": {
+			"name": "TestRequest",
+			"fields": [112],
+			"required": [112]
+		},
+		"2": {
+			"name": "ResendRequest",
+			"fields": [7, 16],
+			"required": [7, 16]
+		},
+		"3": {
+			"name": "Reject",
+			"fields": [45, 371, 372, 373, 58, 354, 355],
+			"required": [45]
+		},
+		"4": {
+			"name": "SequenceReset",
+			"fields": [123, 36],
+			"required": [36]
+		},
+		"5": {
+			"name": "Logout",
+			"fields": [58, 354, 355],
+			"required": []
+		},
+		"A": {
+			"name": "Logon",
+			"fields": [98, 108, 95, 96, 141, 789, 383, 384, 464, 553, 554],
+			"required": [98, 108]
+		},
+		"D": {
+			"name": "NewOrderSingle",
+			"fields": [11, 526, 583, 229, 75, 1, 660, 581, 589, 590, 591, 70, 63, 64, 544, 635, 21, 18, 110, 111, 100, 386, 81, 140, 711, 54, 114, 60, 38, 152, 516, 468, 469, 40, 423, 44, 99, 15, 376, 377, 23, 117, 59, 168, 432, 126, 427, 528, 529, 582, 121, 120, 775, 58, 354, 355, 193, 192, 640, 77, 203, 210, 211, 388, 389, 494],
+			"required": [11, 54, 60, 38, 40],
+			"components": ["Parties", "Instrument", "FinancingDetails", "Stipulations", "CommissionData", "SpreadOrBenchmarkCurveData", "YieldData", "PegInstructions", "DiscretionInstructions"],
+			"requiredComponents": ["Instrument"]
+		},
+		"F": {
+			"name": "OrderCancelRequest",
+			"fields": [41, 37, 11, 526, 583, 586, 66, 1, 660, 581, 711, 54, 60, 38, 152, 516, 468, 469, 376, 58, 354, 355],
+			"required": [41, 11, 54, 60],
+			"components": ["Parties", "Instrument", "FinancingDetails"],
+			"requiredComponents": ["Instrument"]
+		},
+		"G": {
+			"name": "OrderCancelReplaceRequest",
+			"fields": [37, 229, 75, 41, 11, 526, 583, 586, 66, 1, 660, 581, 589, 590, 591, 70, 63, 64, 544, 635, 21, 18, 110, 111, 100, 386, 81, 140, 711, 54, 114, 60, 38, 152, 516, 468, 469, 40, 423, 44, 99, 15, 376, 377, 23, 117, 59, 168, 432, 126, 427, 528, 529, 582, 121, 120, 775, 58, 354, 355, 193, 192, 640, 77, 203, 210, 211, 388, 389, 494],
+			"required": [41, 11, 54, 60, 40],
+			"components": ["Parties", "Instrument", "FinancingDetails", "Stipulations", "CommissionData", "SpreadOrBenchmarkCurveData", "YieldData", "PegInstructions", "DiscretionInstructions"],
+			"requiredComponents": ["Instrument"]
+		},
+		"H": {
+			"name": "OrderStatusRequest",
+			"fields": [37, 11, 526, 790, 66, 1, 660, 581, 711, 54],
+			"required": [11, 54],
+			"components": ["Parties", "Instrument", "FinancingDetails"],
+			"requiredComponents": ["Instrument"]
+		},
+		"8": {
+			"name": "ExecutionReport",
+			"fields": [37, 198, 526, 527, 11, 41, 583, 693, 790, 584, 911, 912, 66, 548, 551, 549, 550, 797, 382, 17, 19, 150, 378, 39, 636, 103, 1, 660, 581, 589, 590, 591, 70, 63, 64, 544, 635, 21, 18, 110, 111, 100, 386, 81, 140, 711, 54, 38, 152, 516, 468, 469, 40, 423, 44, 99, 15, 376, 377, 23, 117, 59, 168, 432, 126, 427, 528, 529, 582, 121, 120, 775, 32, 31, 669, 29, 30, 151, 14, 6, 424, 425, 426, 60, 113, 155, 156, 75, 229, 381, 157, 230, 158, 159, 738, 920, 921, 922, 238, 237, 118, 119, 442, 480, 481, 513, 851, 555, 58, 354, 355, 193, 192, 640, 77, 203, 210, 211, 388, 389, 494],
+			"required": [37, 17, 150, 39, 54, 151, 14, 6],
+			"components": ["Parties", "Instrument", "FinancingDetails", "Stipulations", "CommissionData", "SpreadOrBenchmarkCurveData", "YieldData", "PegInstructions", "DiscretionInstructions", "TrdRegTimestamps", "MiscFees"],
+			"requiredComponents": ["Instrument"]
+		},
+		"9": {
+			"name": "OrderCancelReject",
+			"fields": [37, 198, 526, 11, 583, 41, 39, 636, 586, 66, 1, 660, 581, 60, 434, 102, 58, 354, 355],
+			"required": [37, 11, 41, 39, 434]
+		},
+		"j": {
+			"name": "BusinessMessageReject",
+			"fields": [45, 372, 379, 380, 58, 354, 355],
+			"required": [372, 380]
+		},
+		"V": {
+			"name": "MarketDataRequest",
+			"fields": [262, 263, 264, 265, 267, 266, 286, 146, 386, 815],
+			"required": [262, 263, 264, 267, 146]
+		},
+		"W": {
+			"name": "MarketDataSnapshotFullRefresh",
+			"fields": [262, 711, 555, 291, 292, 813, 268],
+			"required": [268],
+			"components": ["FinancingDetails", "Instrument"],
+			"requiredComponents": ["Instrument"]
+		},
+		"X": {
+			"name": "MarketDataIncrementalRefresh",
+			"fields": [262, 268, 813],
+			"required": [268]
+		},
+		"Y": {
+			"name": "MarketDataRequestReject",
+			"fields": [262, 281, 816, 58, 354, 355],
+			"required": [262]
+		},
+		"R": {
+			"name": "QuoteRequest",
+			"fields": [131, 644, 1, 660, 581, 146, 58, 354, 355],
+			"required": [131, 146]
+		},
+		"S": {
+			"name": "Quote",
+			"fields": [131, 117, 693, 301, 537, 336, 625, 54, 38, 152, 516, 468, 469, 63, 64, 193, 192, 15, 1, 660, 581, 40, 126, 132, 133, 134, 135, 62, 188, 190, 189, 191, 631, 632, 633, 634, 60, 642, 643, 368, 711, 555, 735, 775, 528, 529, 582, 121, 120, 58, 354, 355],
+			"required": [117],
+			"components": ["Parties", "Instrument", "FinancingDetails", "Stipulations", "CommissionData", "SpreadOrBenchmarkCurveData", "YieldData"],
+			"requiredComponents": ["Instrument"]
+		}
+	},
 	"fieldsByTag": {
 		"1": {
 			"name": "Account",
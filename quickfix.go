@@ -0,0 +1,254 @@
+package fixdecoder
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// quickFIXNode any element of a QuickFIX data dictionary: field, value, group, component or message
+type quickFIXNode struct {
+	XMLName     xml.Name
+	Number      string         `xml:"number,attr"`
+	Name        string         `xml:"name,attr"`
+	Type        string         `xml:"type,attr"`
+	Required    string         `xml:"required,attr"`
+	MsgType     string         `xml:"msgtype,attr"`
+	Enum        string         `xml:"enum,attr"`
+	Description string         `xml:"description,attr"`
+	Children    []quickFIXNode `xml:",any"`
+}
+
+// quickFIXDocument QuickFIX data dictionary, e.g. FIX44.xml
+type quickFIXDocument struct {
+	XMLName     xml.Name       `xml:"fix"`
+	Type        string         `xml:"type,attr"`
+	Major       string         `xml:"major,attr"`
+	Minor       string         `xml:"minor,attr"`
+	ServicePack string         `xml:"servicepack,attr"`
+	Header      quickFIXNode   `xml:"header"`
+	Trailer     quickFIXNode   `xml:"trailer"`
+	Messages    []quickFIXNode `xml:"messages>message"`
+	Components  []quickFIXNode `xml:"components>component"`
+	Fields      []quickFIXNode `xml:"fields>field"`
+}
+
+// quickFIXLoader state of a QuickFIX data dictionary being loaded
+type quickFIXLoader struct {
+	dictionary *Dictionary
+	components map[string]*quickFIXNode
+}
+
+// LoadQuickFIXDictionaryFile load a QuickFIX XML data dictionary file, e.g. FIX44.xml
+func LoadQuickFIXDictionaryFile(path string) (*Dictionary, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return LoadQuickFIXDictionary(file)
+}
+
+// LoadQuickFIXDictionary load a QuickFIX XML data dictionary: fields and their enums, header, trailer, messages, components and repeating groups.
+// Groups are defined inline in QuickFIX dictionaries; the members of all the definitions of a group are merged into one GroupDefinition
+func LoadQuickFIXDictionary(r io.Reader) (*Dictionary, error) {
+	document := quickFIXDocument{}
+	if err := xml.NewDecoder(r).Decode(&document); err != nil {
+		return nil, fmt.Errorf("fixdecoder: invalid QuickFIX dictionary: %v", err)
+	}
+
+	version := document.Type + "." + document.Major + "." + document.Minor
+	if document.ServicePack != "" && document.ServicePack != "0" {
+		version += "SP" + document.ServicePack
+	}
+
+	loader := &quickFIXLoader{dictionary: NewDictionary(version), components: make(map[string]*quickFIXNode)}
+	d := loader.dictionary
+
+	for _, field := range document.Fields {
+		definition := &FieldDefinition{
+			Tag:  field.Number,
+			Name: field.Name,
+			Type: strings.ToUpper(field.Type),
+		}
+
+		for _, value := range field.Children {
+			if value.XMLName.Local != "value" {
+				continue
+			}
+
+			if definition.Values == nil {
+				definition.Values = make(map[string]string)
+			}
+			definition.Values[value.Enum] = value.Description
+		}
+
+		d.AddField(definition)
+	}
+
+	for i := range document.Components {
+		loader.components[document.Components[i].Name] = &document.Components[i]
+	}
+
+	var err error
+	for _, component := range document.Components {
+		if d.Components[component.Name], err = loader.definition(component.Name, &component); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, message := range document.Messages {
+		if d.Messages[message.MsgType], err = loader.definition(message.Name, &message); err != nil {
+			return nil, err
+		}
+	}
+
+	if d.Header, err = loader.definition("Header", &document.Header); err != nil {
+		return nil, err
+	}
+
+	if d.Trailer, err = loader.definition("Trailer", &document.Trailer); err != nil {
+		return nil, err
+	}
+
+	required := make(map[string]bool)
+	for _, fieldID := range d.Header.Required {
+		required[fieldID] = true
+	}
+
+	for _, fieldID := range d.Header.Fields {
+		if field := d.Field(fieldID); field != nil {
+			field.IsHeaderField = true
+			field.IsRequired = required[fieldID]
+		}
+	}
+
+	d.linkDataFields()
+	return d, nil
+}
+
+// definition build the definition of the header, the trailer, a component or a message
+func (l *quickFIXLoader) definition(name string, node *quickFIXNode) (*Definition, error) {
+	definition := &Definition{
+		Name:               name,
+		Fields:             make([]string, 0),
+		Required:           make([]string, 0),
+		Components:         make([]string, 0),
+		RequiredComponents: make([]string, 0),
+	}
+
+	for i := range node.Children {
+		child := &node.Children[i]
+		required := child.Required == "Y"
+
+		switch child.XMLName.Local {
+		case "field", "group":
+			tag, err := l.tag(child.Name)
+			if err != nil {
+				return nil, err
+			}
+
+			if child.XMLName.Local == "group" {
+				if err := l.group(tag, child); err != nil {
+					return nil, err
+				}
+			}
+
+			definition.Fields = append(definition.Fields, tag)
+			if required {
+				definition.Required = append(definition.Required, tag)
+			}
+		case "component":
+			if _, found := l.components[child.Name]; !found {
+				return nil, fmt.Errorf("fixdecoder: unknown component %s in %s", child.Name, name)
+			}
+
+			definition.Components = append(definition.Components, child.Name)
+			if required {
+				definition.RequiredComponents = append(definition.RequiredComponents, child.Name)
+			}
+		}
+	}
+
+	return definition, nil
+}
+
+// group register the members of a repeating group. Components used inside the group are flattened into it
+func (l *quickFIXLoader) group(tag string, node *quickFIXNode) error {
+	members, err := l.members(node, make(map[string]bool))
+	if err != nil {
+		return err
+	}
+
+	group := l.dictionary.Group(tag)
+	if group == nil {
+		l.dictionary.Groups[tag] = &GroupDefinition{Fields: members}
+		return nil
+	}
+
+	for _, member := range members {
+		if _, contain := contains(group.Fields, member); !contain {
+			group.Fields = append(group.Fields, member)
+		}
+	}
+
+	return nil
+}
+
+// members the tags of the fields of a group or component, nested group count fields included
+func (l *quickFIXLoader) members(node *quickFIXNode, visiting map[string]bool) ([]string, error) {
+	members := make([]string, 0)
+
+	for i := range node.Children {
+		child := &node.Children[i]
+
+		switch child.XMLName.Local {
+		case "field", "group":
+			tag, err := l.tag(child.Name)
+			if err != nil {
+				return nil, err
+			}
+
+			if child.XMLName.Local == "group" {
+				if err := l.group(tag, child); err != nil {
+					return nil, err
+				}
+			}
+
+			members = append(members, tag)
+		case "component":
+			component, found := l.components[child.Name]
+			if !found {
+				return nil, fmt.Errorf("fixdecoder: unknown component %s", child.Name)
+			}
+
+			if visiting[child.Name] {
+				return nil, fmt.Errorf("fixdecoder: component %s contains itself", child.Name)
+			}
+
+			visiting[child.Name] = true
+			componentMembers, err := l.members(component, visiting)
+			delete(visiting, child.Name)
+			if err != nil {
+				return nil, err
+			}
+
+			members = append(members, componentMembers...)
+		}
+	}
+
+	return members, nil
+}
+
+// tag get the tag of a field by name
+func (l *quickFIXLoader) tag(name string) (string, error) {
+	field := l.dictionary.FieldByName(name)
+	if field == nil {
+		return "", fmt.Errorf("fixdecoder: unknown field %s", name)
+	}
+
+	return field.Tag, nil
+}
@@ -0,0 +1,285 @@
+package fixdecoder
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// quickFIXFileSuffixes suffixes of the files of QuickFIX/J, QuickFIX/n and QuickFIX/Go file stores and file logs
+var quickFIXFileSuffixes = []string{
+	".messages.current.log", ".event.current.log", ".messages.log", ".event.log",
+	".body", ".header", ".seqnums", ".session",
+}
+
+// QuickFIXSessionID the session ID of a QuickFIX store or log file, e.g. FIX.4.4:CLIENT->BROKER for log/FIX.4.4-CLIENT-BROKER.messages.current.log.
+// Empty if the file name does not tell
+func QuickFIXSessionID(path string) string {
+	name := filepath.Base(path)
+	for _, suffix := range quickFIXFileSuffixes {
+		name = strings.TrimSuffix(name, suffix)
+	}
+
+	parts := strings.Split(name, "-")
+	if len(parts) != 3 || !strings.HasPrefix(parts[0], "FIX") {
+		return ""
+	}
+
+	return parts[0] + ":" + parts[1] + "->" + parts[2]
+}
+
+// QuickFIXLogReader read the messages of a QuickFIX/J, QuickFIX/n or QuickFIX/Go file log, messages or event log, one by one:
+//
+//	20260301-10:00:00.123: 8=FIX.4.4|9=...        QuickFIX/J
+//	20260301-10:00:00.123 : 8=FIX.4.4|9=...       QuickFIX/n
+//	2026/03/01 10:00:00.123456 8=FIX.4.4|9=...    QuickFIX/Go
+//
+// Messages are tagged with the timestamp of their line, their session ID and their direction: OUTBOUND if their SenderCompID <49>
+// is the sender of the session, INBOUND otherwise
+type QuickFIXLogReader struct {
+	scanner   *bufio.Scanner
+	extractor *Extractor
+	sessionID string
+	pending   []*ExtractedMessage
+}
+
+// NewQuickFIXLogReader new QuickFIX log reader instance. sessionID is the session of the log, e.g. from QuickFIXSessionID;
+// if empty, it is taken from every message. Options are the ones of NewFixDecoder
+func NewQuickFIXLogReader(r io.Reader, sessionID string, options ...Option) *QuickFIXLogReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, streamReadSize), maxMessageSize)
+
+	return &QuickFIXLogReader{
+		scanner:   scanner,
+		extractor: NewExtractor(options...),
+		sessionID: sessionID,
+	}
+}
+
+// Next the next message of the log. It returns io.EOF at the end of the log
+func (r *QuickFIXLogReader) Next() (*ExtractedMessage, error) {
+	for len(r.pending) == 0 {
+		if !r.scanner.Scan() {
+			if err := r.scanner.Err(); err != nil {
+				return nil, err
+			}
+			return nil, io.EOF
+		}
+
+		r.pending = r.extractor.Extract(r.scanner.Text())
+	}
+
+	message := r.pending[0]
+	r.pending = r.pending[1:]
+
+	sender, target := fieldValue(message.Fields, "49"), fieldValue(message.Fields, "56")
+	if message.SessionID == "" {
+		message.SessionID = r.sessionID
+	}
+	if message.SessionID == "" && sender != "" {
+		message.SessionID = fieldValue(message.Fields, BEGINSTRING) + ":" + sender + "->" + target
+	}
+
+	if message.Direction == "" && message.SessionID != "" {
+		message.Direction = INBOUND
+		if sessionSender(message.SessionID) == sender {
+			message.Direction = OUTBOUND
+		}
+	}
+
+	if message.Timestamp == "" {
+		message.Timestamp = fieldValue(message.Fields, "52")
+	}
+
+	return message, nil
+}
+
+// QuickFIXStore the content of a QuickFIX/J, QuickFIX/n or QuickFIX/Go file store: the messages sent by the session, kept for resend requests,
+// and its next sequence numbers
+type QuickFIXStore struct {
+	SessionID           string
+	NextSenderMsgSeqNum int // 0 if unknown
+	NextTargetMsgSeqNum int // 0 if unknown
+	Messages            []*ExtractedMessage
+	Errors              []error // Parts of the body which are not well formed messages, and messages whose MsgSeqNum <34> is not the one of their header entry
+}
+
+// LoadQuickFIXStoreFiles load the file store of a session from its files, e.g. store/FIX.4.4-CLIENT-BROKER for
+// store/FIX.4.4-CLIENT-BROKER.body, .header and .seqnums. The .header and .seqnums files are optional
+func LoadQuickFIXStoreFiles(prefix string, options ...Option) (*QuickFIXStore, error) {
+	body, err := os.Open(prefix + ".body")
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var header, seqnums io.Reader
+	if file, err := os.Open(prefix + ".header"); err == nil {
+		defer file.Close()
+		header = file
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	if file, err := os.Open(prefix + ".seqnums"); err == nil {
+		defer file.Close()
+		seqnums = file
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	store, err := LoadQuickFIXStore(body, header, seqnums, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	store.SessionID = QuickFIXSessionID(prefix)
+	for _, message := range store.Messages {
+		if message.SessionID == "" {
+			message.SessionID = store.SessionID
+		}
+	}
+
+	return store, nil
+}
+
+// LoadQuickFIXStore load a file store. The .header file indexes the messages of the .body file with "{{seqNum}},{{offset}},{{size}}" entries;
+// without it, the messages of the body are framed by BodyLength <9> and CheckSum <10>. header and seqnums may be nil.
+// A corrupted body does not fail the load: what could not be decoded is reported in the Errors of the store, with its offset
+func LoadQuickFIXStore(body, header, seqnums io.Reader, options ...Option) (*QuickFIXStore, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	store := &QuickFIXStore{}
+	if seqnums != nil {
+		if store.NextSenderMsgSeqNum, store.NextTargetMsgSeqNum, err = parseQuickFIXSeqNums(seqnums); err != nil {
+			return nil, err
+		}
+	}
+
+	decoder := NewFixDecoder(options...)
+	add := func(raw string, offset int, fields DecodedFields) {
+		store.Messages = append(store.Messages, &ExtractedMessage{
+			Raw:       raw,
+			Offset:    offset,
+			Direction: OUTBOUND,
+			Timestamp: fieldValue(fields, "52"),
+			Fields:    fields,
+		})
+	}
+
+	if header == nil {
+		sd := NewStreamDecoder(bytes.NewReader(data), options...)
+		for {
+			msg, err := sd.Next()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				store.Errors = append(store.Errors, err)
+				continue
+			}
+
+			add(msg.Raw, msg.Offset, msg.Fields)
+		}
+	} else {
+		index, err := io.ReadAll(header)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range strings.Fields(string(index)) {
+			values := strings.Split(entry, ",")
+			if len(values) != 3 {
+				return nil, fmt.Errorf("fixdecoder: invalid QuickFIX store header entry %q", entry)
+			}
+
+			seqNum, err0 := strconv.Atoi(values[0])
+			offset, err1 := strconv.Atoi(values[1])
+			size, err2 := strconv.Atoi(values[2])
+			if err0 != nil || err1 != nil || err2 != nil || offset < 0 || size < 0 {
+				return nil, fmt.Errorf("fixdecoder: invalid QuickFIX store header entry %q", entry)
+			}
+
+			if offset+size > len(data) {
+				// the body was cut short
+				store.Errors = append(store.Errors, &ErrTruncated{Offset: len(data)})
+				continue
+			}
+
+			raw := string(data[offset : offset+size])
+			var fields DecodedFields
+			if msg, err := decoder.DecodeE(raw); err == nil {
+				fields = msg.Fields
+			} else {
+				store.Errors = append(store.Errors, withOffset(err, offset))
+				fields = decoder.Decode(raw)
+			}
+
+			msgSeqNum := fieldValue(fields, "34")
+			if n, err := strconv.Atoi(msgSeqNum); err != nil || n != seqNum {
+				store.Errors = append(store.Errors, &ErrSeqNumMismatch{Offset: offset, Expected: seqNum, Actual: msgSeqNum})
+			}
+
+			add(raw, offset, fields)
+		}
+	}
+
+	return store, nil
+}
+
+// parseQuickFIXSeqNums parse a .seqnums file: "{{nextSenderMsgSeqNum}} : {{nextTargetMsgSeqNum}}", zero padded.
+// QuickFIX/J writes it with a two bytes length prefix, which is skipped
+func parseQuickFIXSeqNums(r io.Reader) (int, int, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	text := strings.TrimLeftFunc(string(data), func(c rune) bool {
+		return c < '0' || c > '9'
+	})
+
+	values := strings.Split(text, ":")
+	if len(values) != 2 {
+		return 0, 0, fmt.Errorf("fixdecoder: invalid QuickFIX seqnums %q", data)
+	}
+
+	sender, err1 := strconv.Atoi(strings.TrimSpace(values[0]))
+	target, err2 := strconv.Atoi(strings.TrimSpace(values[1]))
+	if err1 != nil || err2 != nil {
+		return 0, 0, fmt.Errorf("fixdecoder: invalid QuickFIX seqnums %q", data)
+	}
+
+	return sender, target, nil
+}
+
+// sessionSender the SenderCompID of a session ID, e.g. CLIENT for FIX.4.4:CLIENT->BROKER
+func sessionSender(sessionID string) string {
+	if i := strings.IndexByte(sessionID, ':'); i >= 0 {
+		sessionID = sessionID[i+1:]
+	}
+
+	if i := strings.Index(sessionID, "->"); i >= 0 {
+		return sessionID[:i]
+	}
+
+	return sessionID
+}
+
+// fieldValue the value of the first field with the given id, empty if there is none
+func fieldValue(dfs DecodedFields, fieldID string) string {
+	for _, df := range dfs {
+		if df.FieldID == fieldID {
+			return df.Value
+		}
+	}
+
+	return ""
+}
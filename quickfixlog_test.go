@@ -0,0 +1,129 @@
+package fixdecoder_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	fixdecoder "github.com/ilovelili/FixDecoder"
+)
+
+func TestQuickFIXLogReader(t *testing.T) {
+	logon := fixdecoder.NewMessageBuilder("FIX.4.4", "A").SetField("49", "CLIENT").SetField("56", "BROKER").SetField("52", "20260301-10:00:00.120").String()
+	heartbeat := fixdecoder.NewMessageBuilder("FIX.4.4", "0").SetField("49", "BROKER").SetField("56", "CLIENT").SetField("52", "20260301-10:00:30.000").String()
+
+	logs := []string{
+		"20260301-10:00:00.123: " + logon + "\n20260301-10:00:30.001: " + heartbeat + "\n",             // QuickFIX/J
+		"20260301-10:00:00.123 : " + logon + "\n20260301-10:00:30.001 : " + heartbeat + "\n",           // QuickFIX/n
+		"2026/03/01 10:00:00.123 " + logon + "\nsession started\n2026/03/01 10:00:30.001 " + heartbeat, // QuickFIX/Go
+	}
+
+	for _, log := range logs {
+		r := fixdecoder.NewQuickFIXLogReader(strings.NewReader(log), fixdecoder.QuickFIXSessionID("log/FIX.4.4-CLIENT-BROKER.messages.current.log"))
+
+		actual := make([]string, 0)
+		for {
+			m, err := r.Next()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			actual = append(actual, strings.Join([]string{m.SessionID, m.Direction, m.Timestamp[len(m.Timestamp)-12:], m.Fields[2].Value}, " "))
+		}
+
+		expect := "FIX.4.4:CLIENT->BROKER OUT 10:00:00.123 A,FIX.4.4:CLIENT->BROKER IN 10:00:30.001 0"
+		if strings.Join(actual, ",") != expect {
+			t.Errorf("expect %s, actual %s", expect, strings.Join(actual, ","))
+		}
+	}
+}
+
+func TestLoadQuickFIXStoreFiles(t *testing.T) {
+	messages := []string{
+		fixdecoder.NewMessageBuilder("FIX.4.4", "A").SetField("49", "CLIENT").SetField("56", "BROKER").SetField("34", "1").String(),
+		fixdecoder.NewMessageBuilder("FIX.4.4", "D").SetField("49", "CLIENT").SetField("56", "BROKER").SetField("34", "2").SetField("11", "ORD1").String(),
+	}
+
+	body := strings.Join(messages, "")
+	headers := []string{
+		"1,0," + strconv.Itoa(len(messages[0])) + " 2," + strconv.Itoa(len(messages[0])) + "," + strconv.Itoa(len(messages[1])) + " ",   // QuickFIX/J and QuickFIX/n
+		"1,0," + strconv.Itoa(len(messages[0])) + "\n2," + strconv.Itoa(len(messages[0])) + "," + strconv.Itoa(len(messages[1])) + "\n", // QuickFIX/Go
+		"", // no header: the body is framed
+	}
+
+	for _, header := range headers {
+		prefix := filepath.Join(t.TempDir(), "FIX.4.4-CLIENT-BROKER")
+		writeFile(t, prefix+".body", body)
+		writeFile(t, prefix+".seqnums", "\x00\x2d0000000000000000003 : 0000000000000000005")
+		if header != "" {
+			writeFile(t, prefix+".header", header)
+		}
+
+		store, err := fixdecoder.LoadQuickFIXStoreFiles(prefix)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if store.SessionID != "FIX.4.4:CLIENT->BROKER" || store.NextSenderMsgSeqNum != 3 || store.NextTargetMsgSeqNum != 5 || len(store.Messages) != 2 || len(store.Errors) != 0 {
+			t.Fatalf("expect store of 2 messages, actual %+v", store)
+		}
+
+		if m := store.Messages[1]; m.Raw != messages[1] || m.Offset != len(messages[0]) || m.Direction != fixdecoder.OUTBOUND || m.SessionID != store.SessionID {
+			t.Errorf("expect %q, actual %+v", messages[1], m)
+		}
+	}
+}
+
+func TestLoadQuickFIXStore_Errors(t *testing.T) {
+	first := fixdecoder.NewMessageBuilder("FIX.4.4", "A").SetField("49", "CLIENT").SetField("56", "BROKER").SetField("34", "1").String()
+	second := fixdecoder.NewMessageBuilder("FIX.4.4", "D").SetField("49", "CLIENT").SetField("56", "BROKER").SetField("34", "3").String()
+
+	// the body is framed: the garbled message is reported, not skipped silently
+	garbled := strings.Replace(first, "9=", "9=x", 1)
+	store, err := fixdecoder.LoadQuickFIXStore(strings.NewReader(garbled+second), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(store.Messages) != 1 || len(store.Errors) != 1 {
+		t.Fatalf("expect 1 message and 1 error, actual %+v", store)
+	}
+
+	// the header indexes the second message as 2, but its MsgSeqNum is 3
+	header := "1,0," + strconv.Itoa(len(first)) + " 2," + strconv.Itoa(len(first)) + "," + strconv.Itoa(len(second))
+	store, err = fixdecoder.LoadQuickFIXStore(strings.NewReader(first+second), strings.NewReader(header), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(store.Errors) != 1 {
+		t.Fatalf("expect 1 error, actual %v", store.Errors)
+	}
+
+	mismatch, ok := store.Errors[0].(*fixdecoder.ErrSeqNumMismatch)
+	if !ok || mismatch.Offset != len(first) || mismatch.Expected != 2 || mismatch.Actual != "3" {
+		t.Errorf("expect MsgSeqNum 3 indexed as 2, actual %v", store.Errors[0])
+	}
+
+	// the body is cut in the middle of the second message
+	store, err = fixdecoder.LoadQuickFIXStore(strings.NewReader(first+second[:10]), strings.NewReader(header), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	truncated, ok := store.Errors[0].(*fixdecoder.ErrTruncated)
+	if len(store.Messages) != 1 || len(store.Errors) != 1 || !ok || truncated.Offset != len(first)+10 {
+		t.Errorf("expect 1 message and a truncated body, actual %+v %v", store.Messages, store.Errors)
+	}
+}
+
+func writeFile(t *testing.T, path, data string) {
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
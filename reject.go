@@ -0,0 +1,176 @@
+package fixdecoder
+
+import (
+	"strconv"
+	"time"
+)
+
+// sessionRejectReasons the default SessionRejectReason <373> of the Reject <35=3> of each validation issue code, see WithSessionRejectReasons.
+// Garbled messages are not rejected, see WithGarbledRejects
+var sessionRejectReasons = map[string]string{
+	MISSINGFIELD:       "1",  // Required tag missing
+	FIELDNOTALLOWED:    "2",  // Tag not defined for this message type
+	INVALIDENUMVALUE:   "5",  // Value is incorrect (out of range) for this tag
+	INVALIDTYPE:        "6",  // Incorrect data format for value
+	INVALIDDATALENGTH:  "6",  // Incorrect data format for value
+	MISSINGLENGTHFIELD: "6",  // Incorrect data format for value
+	UNKNOWNMSGTYPE:     "11", // Invalid MsgType
+	DUPLICATEFIELD:     "13", // Tag appears more than once
+	MISPLACEDFIELD:     "14", // Tag specified out of required order
+	INVALIDGROUPCOUNT:  "16", // Incorrect NumInGroup count for repeating group
+}
+
+// RejectOption reject option, see RejectFor
+type RejectOption func(*rejectOptions)
+
+// rejectOptions how RejectFor builds rejects
+type rejectOptions struct {
+	sessionRejectReasons  map[string]string
+	businessRejectReasons map[string]string
+	rejectGarbled         bool
+}
+
+// WithSessionRejectReasons the SessionRejectReason <373> of the Reject <35=3> of validation issue codes, e.g. the ones of house rules,
+// on top of the default ones: MISSINGFIELD 1, FIELDNOTALLOWED 2, INVALIDENUMVALUE 5, INVALIDTYPE, INVALIDDATALENGTH and MISSINGLENGTHFIELD 6,
+// UNKNOWNMSGTYPE 11, DUPLICATEFIELD 13, MISPLACEDFIELD 14 and INVALIDGROUPCOUNT 16
+func WithSessionRejectReasons(reasons map[string]string) RejectOption {
+	return func(o *rejectOptions) {
+		o.sessionRejectReasons = reasons
+	}
+}
+
+// WithBusinessRejectReasons the BusinessRejectReason <380> of the BusinessMessageReject <35=j> of application-level validation issue codes,
+// e.g. the ones of house rules. Codes without a session or business reject reason are rejected with reason 0 (Other)
+func WithBusinessRejectReasons(reasons map[string]string) RejectOption {
+	return func(o *rejectOptions) {
+		o.businessRejectReasons = reasons
+	}
+}
+
+// WithGarbledRejects reject garbled messages, whose BodyLength <9> or CheckSum <10> is wrong or missing, with SessionRejectReason 5 (Value is incorrect)
+// or 1 (Required tag missing). By default they are ignored, as FIX expects: no reject is built
+func WithGarbledRejects() RejectOption {
+	return func(o *rejectOptions) {
+		o.rejectGarbled = true
+	}
+}
+
+// RejectFor the reject of a message which failed validation, for its first ERROR issue: a Reject <35=3> if the issue code has a session reject
+// reason (see WithSessionRejectReasons) or if the message is session-level, a BusinessMessageReject <35=j> otherwise. nil if there is no ERROR issue, or if the message is garbled
+// (wrong or missing BodyLength <9> or CheckSum <10>) unless WithGarbledRejects is given
+func RejectFor(dfs DecodedFields, issues []*ValidationIssue, options ...RejectOption) *MessageBuilder {
+	o := &rejectOptions{}
+	for _, option := range options {
+		option(o)
+	}
+
+	var first *ValidationIssue
+	for _, issue := range issues {
+		if issue.Severity != ERROR {
+			continue
+		}
+
+		if garbled(issue) && !o.rejectGarbled {
+			return nil
+		}
+
+		if first == nil {
+			first = issue
+		}
+	}
+
+	if first == nil {
+		return nil
+	}
+
+	if first.Code == INVALIDCHECKSUM || first.Code == INVALIDBODYLENGTH {
+		return NewReject(dfs, first.Tag, "5", first.Message)
+	}
+
+	if reason, found := o.sessionRejectReasons[first.Code]; found {
+		return NewReject(dfs, first.Tag, reason, first.Message)
+	}
+
+	if reason, found := sessionRejectReasons[first.Code]; found {
+		return NewReject(dfs, first.Tag, reason, first.Message)
+	}
+
+	if _, session := contains(sessionMsgTypes, fieldValue(dfs, MSGTYPE)); session {
+		return NewReject(dfs, first.Tag, "99", first.Message)
+	}
+
+	reason, found := o.businessRejectReasons[first.Code]
+	if !found {
+		reason = "0"
+	}
+
+	return NewBusinessMessageReject(dfs, reason, first.Message)
+}
+
+// garbled whether an issue makes its message garbled: a wrong or missing BodyLength <9> or CheckSum <10>
+func garbled(issue *ValidationIssue) bool {
+	switch issue.Code {
+	case INVALIDBODYLENGTH, INVALIDCHECKSUM:
+		return true
+	case MISSINGFIELD:
+		return issue.Tag == 9 || issue.Tag == 10
+	}
+
+	return false
+}
+
+// NewReject a Reject <35=3> of a message: RefSeqNum <45>, RefTagID <371> (if refTagID is positive), RefMsgType <372>, SessionRejectReason <373> and Text <58>
+// (if not empty). The reject goes back to the sender of the message; MsgSeqNum <34> is left to the caller, and SendingTime <52> is now
+func NewReject(dfs DecodedFields, refTagID int, reason, text string) *MessageBuilder {
+	b := newReply(dfs, "3")
+	if refTagID > 0 {
+		b.SetField("371", strconv.Itoa(refTagID))
+	}
+	b.SetField("373", reason)
+
+	if text != "" {
+		b.SetField("58", text)
+	}
+
+	return b
+}
+
+// NewBusinessMessageReject a BusinessMessageReject <35=j> of a message: RefSeqNum <45>, RefMsgType <372>, BusinessRejectRefID <379> (the ClOrdID <11>
+// of the message, if any), BusinessRejectReason <380> and Text <58> (if not empty). The reject goes back to the sender of the message;
+// MsgSeqNum <34> is left to the caller, and SendingTime <52> is now
+func NewBusinessMessageReject(dfs DecodedFields, reason, text string) *MessageBuilder {
+	b := newReply(dfs, "j")
+	if clOrdID := fieldValue(dfs, "11"); clOrdID != "" {
+		b.SetField("379", clOrdID)
+	}
+	b.SetField("380", reason)
+
+	if text != "" {
+		b.SetField("58", text)
+	}
+
+	return b
+}
+
+// newReply a message of type msgType back to the sender of a message, referring to it
+func newReply(dfs DecodedFields, msgType string) *MessageBuilder {
+	beginString := fieldValue(dfs, BEGINSTRING)
+	if beginString == "" {
+		beginString = "FIX.4.4"
+	}
+
+	b := NewMessageBuilder(beginString, msgType)
+	for _, field := range []FieldValue{
+		{FieldID: "49", Value: fieldValue(dfs, "56")},
+		{FieldID: "56", Value: fieldValue(dfs, "49")},
+		{FieldID: "52", Value: time.Now().UTC().Format(UTCTIMESTAMPLAYOUT + ".000")},
+		{FieldID: "45", Value: fieldValue(dfs, "34")},
+		{FieldID: "372", Value: fieldValue(dfs, MSGTYPE)},
+	} {
+		if field.Value != "" {
+			b.SetField(field.FieldID, field.Value)
+		}
+	}
+
+	return b
+}
@@ -0,0 +1,82 @@
+package fixdecoder_test
+
+import (
+	"testing"
+
+	fixdecoder "github.com/ilovelili/FixDecoder"
+)
+
+func TestRejectFor(t *testing.T) {
+	order := fd.Decode("8=FIX.4.4|9=0|35=D|49=CLIENT|56=BROKER|34=2|52=20260301-10:00:00.000|11=ORD1|55=IBM|38=100|40=1|60=20260301-10:00:00.000|10=000|")
+
+	reject := rejectFields(t, fixdecoder.RejectFor(order, (&fixdecoder.MessageValidator{}).Validate(order)))
+	for tag, expect := range map[int]string{35: "3", 49: "BROKER", 56: "CLIENT", 45: "2", 371: "54", 372: "D", 373: "1"} {
+		if df := reject.Field(tag); df == nil || df.Value != expect {
+			t.Errorf("expect %d=%s in the Reject", tag, expect)
+		}
+	}
+
+	issues := []*fixdecoder.ValidationIssue{
+		{Tag: 55, Severity: fixdecoder.WARNING, Code: fixdecoder.DUPLICATEFIELD},
+		{Tag: 55, Severity: fixdecoder.ERROR, Code: "unknown-security", Message: "unknown security IBM"},
+	}
+
+	reasons := fixdecoder.WithBusinessRejectReasons(map[string]string{"unknown-security": "2"})
+	reject = rejectFields(t, fixdecoder.RejectFor(order, issues, reasons))
+	for tag, expect := range map[int]string{35: "j", 45: "2", 372: "D", 379: "ORD1", 380: "2", 58: "unknown security IBM"} {
+		if df := reject.Field(tag); df == nil || df.Value != expect {
+			t.Errorf("expect %d=%s in the BusinessMessageReject", tag, expect)
+		}
+	}
+
+	heartbeat := fd.Decode("8=FIX.4.4|9=0|35=0|49=CLIENT|56=BROKER|34=3|10=000|")
+	reject = rejectFields(t, fixdecoder.RejectFor(heartbeat, issues))
+	if reject.Field(35).Value != "3" || reject.Field(373).Value != "99" {
+		t.Errorf("expect a Reject with reason 99 for a session-level message")
+	}
+
+	if b := fixdecoder.RejectFor(order, issues[:1]); b != nil {
+		t.Errorf("expect no reject for warnings, actual %s", b)
+	}
+
+	if reject := rejectFields(t, fixdecoder.RejectFor(order, issues)); reject.Field(380).Value != "0" {
+		t.Errorf("expect BusinessRejectReason 0 without mapping, actual %s", reject.Field(380).Value)
+	}
+
+	reject = rejectFields(t, fixdecoder.RejectFor(order, issues, reasons, fixdecoder.WithSessionRejectReasons(map[string]string{"unknown-security": "5"})))
+	if reject.Field(35).Value != "3" || reject.Field(371).Value != "55" || reject.Field(373).Value != "5" {
+		t.Errorf("expect a Reject of Symbol with reason 5, actual %s", reject.Raw)
+	}
+}
+
+func TestRejectFor_Garbled(t *testing.T) {
+	order := fd.Decode("8=FIX.4.4|9=0|35=D|49=CLIENT|56=BROKER|34=2|11=ORD1|10=000|")
+	issues := append((&fixdecoder.MessageValidator{}).Validate(order), order.Validate()...)
+
+	if b := fixdecoder.RejectFor(order, issues); b != nil {
+		t.Errorf("expect garbled message ignored, actual %s", b)
+	}
+
+	reject := rejectFields(t, fixdecoder.RejectFor(order, order.Validate(), fixdecoder.WithGarbledRejects()))
+	if reject.Field(371).Value != "9" || reject.Field(373).Value != "5" {
+		t.Errorf("expect a Reject of BodyLength with reason 5, actual %s", reject.Raw)
+	}
+}
+
+// rejectFields decode a built reject, and check its BodyLength and CheckSum
+func rejectFields(t *testing.T, b *fixdecoder.MessageBuilder) *fixdecoder.Message {
+	if b == nil {
+		t.Fatal("expect a reject")
+	}
+
+	msg, err := fd.DecodeE(b.String())
+	if err != nil {
+		t.Fatalf("expect no error, actual %v", err)
+	}
+
+	if issues := msg.Fields.Validate(); len(issues) != 0 {
+		t.Errorf("expect valid reject, actual %v", issues)
+	}
+
+	return msg
+}
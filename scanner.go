@@ -0,0 +1,290 @@
+package fixdecoder
+
+// maxTagDigits tags longer than this are malformed
+const maxTagDigits = 9
+
+// Scanner strict tag=value scanner over a single FIX message. The message must start with BeginString <8>, every field must be
+// {{tag}}={{value}} followed by a delimiter, and the message must end with CheckSum <10>. The delimiter is detected from the message unless set by SetDelimiter.
+// Values are sub-slices of the message and nothing is allocated, so a Scanner can be reused with Reset for many messages:
+//
+//	var s fixdecoder.Scanner
+//	s.Reset(message)
+//	for s.Next() {
+//		s.Tag(), s.Value()
+//	}
+//	if err := s.Err(); err != nil {
+//		...
+//	}
+type Scanner struct {
+	data    []byte
+	pos     int
+	fields  int
+	tag     int
+	value   []byte
+	offset  int
+	eq      int // Position of the '=' of the current field
+	done    bool
+	err     error
+	lenient bool // Whether anything which is not a field is skipped instead of failing, see nextLenient
+
+	dictionary  *Dictionary
+	delimiter   string           // Set by SetDelimiter, empty to detect it
+	separator   string           // Delimiter of the message, empty for any of SOH, '|' and ';'
+	lengthField *FieldDefinition // LENGTH field right before the current position, if any
+	length      int              // Its value
+}
+
+// NewScanner new scanner instance
+func NewScanner(data []byte) *Scanner {
+	s := &Scanner{}
+	s.Reset(data)
+	return s
+}
+
+// Reset scan another message. Trailing line breaks, common when messages are copied from logs, are ignored
+func (s *Scanner) Reset(data []byte) {
+	for len(data) > 0 && (data[len(data)-1] == '\n' || data[len(data)-1] == '\r') {
+		data = data[:len(data)-1]
+	}
+
+	*s = Scanner{data: data, dictionary: s.dictionary, delimiter: s.delimiter, separator: s.delimiter, lenient: s.lenient}
+	if s.delimiter == "" {
+		s.separator = DetectDelimiter(data)
+	}
+}
+
+// SetDelimiter the delimiter of the fields, e.g. SOH or PIPE. Empty to detect it from every message, see DetectDelimiter
+func (s *Scanner) SetDelimiter(delimiter string) {
+	s.delimiter, s.separator = delimiter, delimiter
+	if delimiter == "" {
+		s.separator = DetectDelimiter(s.data)
+	}
+}
+
+// Delimiter the delimiter of the message, empty if it has none and SOH, '|' and ';' are all delimiters
+func (s *Scanner) Delimiter() string {
+	return s.separator
+}
+
+// SetDictionary the dictionary telling which fields are DATA fields, whose value is exactly as long as the LENGTH field before them
+// (e.g. RawDataLength <95> and RawData <96>) and may contain delimiters. The built-in dictionary if not set
+func (s *Scanner) SetDictionary(dictionary *Dictionary) {
+	s.dictionary = dictionary
+}
+
+// Next advance to the next field. It returns false at the end of the message or on error, see Err
+func (s *Scanner) Next() bool {
+	if s.done || s.err != nil {
+		return false
+	}
+
+	if s.lenient {
+		return s.nextLenient()
+	}
+
+	if s.pos >= len(s.data) {
+		if s.fields == 0 {
+			s.err = &ErrNoBeginString{Offset: s.pos}
+		} else {
+			s.err = &ErrTruncated{Offset: s.pos}
+		}
+		return false
+	}
+
+	start := s.pos
+	end, size := nextDelimiter(s.data, start, s.separator)
+
+	eq, tag, valid := -1, 0, s.data[start] != '0'
+	for i := start; i < end; i++ {
+		c := s.data[i]
+		if c == '=' {
+			eq = i
+			break
+		}
+
+		if c < '0' || c > '9' || i-start >= maxTagDigits {
+			valid = false
+		} else {
+			tag = tag*10 + int(c-'0')
+		}
+	}
+
+	if n, found := s.dataLength(tag); found && eq >= 0 && valid {
+		// a DATA field ends after exactly n bytes, even if they hold delimiters. If it does not, fall back to the first delimiter
+		if dataEnd := eq + 1 + n; dataEnd == len(s.data) {
+			end, size = dataEnd, 0
+		} else if dataSize := delimiterAt(s.data, dataEnd, s.separator); dataSize > 0 {
+			end, size = dataEnd, dataSize
+		}
+	}
+
+	switch {
+	case eq < 0 && s.fields == 0:
+		s.err = &ErrNoBeginString{Offset: start, Raw: string(s.data[start:end])}
+	case eq < 0:
+		s.err = &ErrGarbageBetweenFields{Offset: start, Raw: string(s.data[start:end])}
+	case eq == start || !valid:
+		s.err = &ErrMalformedField{Offset: start, Raw: string(s.data[start:end])}
+	case s.fields == 0 && tag != 8:
+		s.err = &ErrNoBeginString{Offset: start, Raw: string(s.data[start:end])}
+	case end == len(s.data):
+		// every field, CheckSum included, is terminated by a delimiter
+		s.err = &ErrTruncated{Offset: end}
+	}
+
+	if s.err != nil {
+		return false
+	}
+
+	s.tag, s.value, s.offset, s.eq = tag, s.data[eq+1:end], start, eq
+	s.fields++
+	s.pos = end + size
+	s.lengthField, s.length = lengthField(s.dict(), tag, s.value)
+
+	if tag == 10 {
+		s.done = true
+		if s.pos < len(s.data) {
+			// reported by Err once the CheckSum field has been consumed
+			s.err = &ErrGarbageBetweenFields{Offset: s.pos, Raw: string(s.data[s.pos:])}
+		}
+	}
+
+	return true
+}
+
+// nextLenient advance to the next {{fieldId}}={{value}} field, skipping anything else: the field id is the run of digits before the first '='
+// preceded by a digit, so that text glued to a field (e.g. a log prefix) is skipped too. Used by Decode, it never fails
+func (s *Scanner) nextLenient() bool {
+	for s.pos < len(s.data) {
+		start := s.pos
+		end, size := nextDelimiter(s.data, start, s.separator)
+		s.pos = end + size
+
+		for eq := start + 1; eq < end; eq++ {
+			if s.data[eq] != '=' || !isDigit(s.data[eq-1]) {
+				continue
+			}
+
+			tagStart := eq - 1
+			for tagStart > start && isDigit(s.data[tagStart-1]) {
+				tagStart--
+			}
+
+			tag := -1
+			if eq-tagStart <= maxTagDigits {
+				tag = 0
+				for _, c := range s.data[tagStart:eq] {
+					tag = tag*10 + int(c-'0')
+				}
+			}
+
+			if n, found := s.dataLength(tag); found {
+				// a DATA field ends after exactly n bytes, even if they hold delimiters
+				if dataEnd := eq + 1 + n; dataEnd == len(s.data) {
+					end, size = dataEnd, 0
+				} else if dataSize := delimiterAt(s.data, dataEnd, s.separator); dataSize > 0 {
+					end, size = dataEnd, dataSize
+				}
+				s.pos = end + size
+			}
+
+			s.tag, s.value, s.offset, s.eq = tag, s.data[eq+1:end], tagStart, eq
+			s.fields++
+			s.lengthField, s.length = lengthField(s.dict(), tag, s.value)
+			return true
+		}
+	}
+
+	s.done = true
+	return false
+}
+
+// dict the dictionary of the scanner
+func (s *Scanner) dict() *Dictionary {
+	if s.dictionary == nil {
+		s.dictionary = DefaultDictionary()
+	}
+
+	return s.dictionary
+}
+
+// dataLength the length of the field tag if it is a DATA field right after its LENGTH field
+func (s *Scanner) dataLength(tag int) (int, bool) {
+	return dataLength(s.dict(), tag, s.lengthField, s.length)
+}
+
+// lengthField if tag is a LENGTH field, its definition and value
+func lengthField(dictionary *Dictionary, tag int, value []byte) (*FieldDefinition, int) {
+	field := dictionary.FieldByTag(tag)
+	if field == nil || field.Type != "LENGTH" || len(value) == 0 || len(value) > maxTagDigits {
+		return nil, 0
+	}
+
+	length := 0
+	for _, c := range value {
+		if !isDigit(c) {
+			return nil, 0
+		}
+		length = length*10 + int(c-'0')
+	}
+
+	return field, length
+}
+
+// dataLength the length of the field tag if it is the DATA field sized by previous, a LENGTH field of the given value
+func dataLength(dictionary *Dictionary, tag int, previous *FieldDefinition, length int) (int, bool) {
+	if previous == nil {
+		return 0, false
+	}
+
+	field := dictionary.FieldByTag(tag)
+	if field == nil || field.Type != "DATA" || field.LengthField != previous.Tag {
+		return 0, false
+	}
+
+	return length, true
+}
+
+// Tag tag of the current field
+func (s *Scanner) Tag() int {
+	return s.tag
+}
+
+// Value value of the current field, a sub-slice of the message
+func (s *Scanner) Value() []byte {
+	return s.value
+}
+
+// Offset byte offset of the current field within the message
+func (s *Scanner) Offset() int {
+	return s.offset
+}
+
+// Err the error which stopped the scan: *ErrNoBeginString, *ErrMalformedField, *ErrTruncated or *ErrGarbageBetweenFields. nil if the message is well formed
+func (s *Scanner) Err() error {
+	return s.err
+}
+
+// isDelimiter whether c is one of the field delimiters
+func isDelimiter(c byte) bool {
+	return c == '\x01' || c == '|' || c == ';'
+}
+
+// isDigit whether c is an ascii digit
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// atoi parse a tag made of digits only. -1 if it does not fit in maxTagDigits
+func atoi(digits string) int {
+	if len(digits) > maxTagDigits {
+		return -1
+	}
+
+	n := 0
+	for i := 0; i < len(digits); i++ {
+		n = n*10 + int(digits[i]-'0')
+	}
+
+	return n
+}
@@ -0,0 +1,317 @@
+package fixdecoder
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Sequence events found by SequenceAnalyzer
+const (
+	GAP           = "gap"            // MsgSeqNum <34> higher than expected: From-To are missing
+	DUPLICATE     = "duplicate"      // MsgSeqNum <34> already received, without PossDupFlag <43>
+	OUTOFORDER    = "out-of-order"   // MsgSeqNum <34> lower than expected and not received yet, without PossDupFlag <43>
+	POSSDUP       = "possdup"        // PossDupFlag <43> set: a resent message
+	POSSRESEND    = "possresend"     // PossResend <97> set: a message possibly sent before under another MsgSeqNum <34>
+	SEQUENCERESET = "sequence-reset" // SequenceReset <35=4> in reset mode: the next MsgSeqNum <34> is To+1, whatever is missing
+	BADRESET      = "bad-reset"      // SequenceReset <35=4> in reset mode with NewSeqNo <36> From below the expected MsgSeqNum To+1: ignored
+	GAPFILL       = "gap-fill"       // SequenceReset <35=4> with GapFillFlag <123>: From-To are skipped on purpose
+)
+
+// SequenceEvent something notable about the MsgSeqNum <34> of a message
+type SequenceEvent struct {
+	Kind      string
+	Session   string // Direction of the session, {{SenderCompID}}->{{TargetCompID}}
+	Index     int    // Index of the message in the analyzed stream
+	MsgSeqNum int
+	From      int // Range of sequence numbers concerned, see the event kinds
+	To        int
+}
+
+// String the event, e.g. "gap CLIENT->BROKER 3-4 (MsgSeqNum 5)"
+func (e *SequenceEvent) String() string {
+	if e.From == 0 && e.To == 0 {
+		return fmt.Sprintf("%s %s (MsgSeqNum %d)", e.Kind, e.Session, e.MsgSeqNum)
+	}
+
+	return fmt.Sprintf("%s %s %d-%d (MsgSeqNum %d)", e.Kind, e.Session, e.From, e.To, e.MsgSeqNum)
+}
+
+// SeqRange a range of sequence numbers, From to To included
+type SeqRange struct {
+	From int
+	To   int
+}
+
+// String the range, e.g. "20-21", or "20" for a single sequence number
+func (r SeqRange) String() string {
+	if r.From == r.To {
+		return strconv.Itoa(r.From)
+	}
+
+	return strconv.Itoa(r.From) + "-" + strconv.Itoa(r.To)
+}
+
+// ResendStatus a ResendRequest <35=2>, and whether the messages it asked for were all resent or gap filled afterwards
+type ResendStatus struct {
+	Session    string // Direction asked to resend, {{SenderCompID}}->{{TargetCompID}}
+	Index      int    // Index of the ResendRequest in the analyzed stream
+	BeginSeqNo int
+	EndSeqNo   int        // As requested: 0 for infinity, or 999999 before FIX.4.3
+	Missing    []SeqRange // Sequence numbers neither resent nor gap filled, up to the last sequence number received when the request was sent
+	Satisfied  bool
+}
+
+// SequenceAnalyzer analyze the sequence numbers of an ordered stream of messages, per direction of every SenderCompID/TargetCompID pair:
+// gaps, duplicates, out of order numbers, resent messages, sequence resets, and resend requests.
+// Sequence numbers are tracked as ranges, so that huge NewSeqNo <36> or EndSeqNo <16> values cost nothing
+type SequenceAnalyzer struct {
+	Events  []*SequenceEvent
+	streams map[string]*sequenceStream
+	resends []*resendRequest
+	count   int
+}
+
+// sequenceStream one direction of a session
+type sequenceStream struct {
+	expected int // Next expected MsgSeqNum, 0 until the first message
+	last     int // Highest MsgSeqNum received
+	received seqRanges
+}
+
+// resendRequest a ResendRequest and the sequence numbers resent or gap filled since
+type resendRequest struct {
+	status  *ResendStatus
+	end     int
+	covered seqRanges
+}
+
+// NewSequenceAnalyzer new sequence analyzer instance
+func NewSequenceAnalyzer() *SequenceAnalyzer {
+	return &SequenceAnalyzer{streams: make(map[string]*sequenceStream)}
+}
+
+// Add analyze the next message of the stream. Messages without a positive MsgSeqNum <34> are skipped
+func (a *SequenceAnalyzer) Add(dfs DecodedFields) {
+	index := a.count
+	a.count++
+
+	msgSeqNum, err := strconv.Atoi(fieldValue(dfs, "34"))
+	if err != nil || msgSeqNum <= 0 {
+		return
+	}
+
+	sender, target := fieldValue(dfs, "49"), fieldValue(dfs, "56")
+	session := sender + "->" + target
+	stream := a.stream(session)
+	msgType := fieldValue(dfs, MSGTYPE)
+	possDup := fieldValue(dfs, "43") == "Y"
+
+	event := func(kind string, from, to int) {
+		a.Events = append(a.Events, &SequenceEvent{Kind: kind, Session: session, Index: index, MsgSeqNum: msgSeqNum, From: from, To: to})
+	}
+
+	if possDup {
+		event(POSSDUP, 0, 0)
+	}
+
+	if fieldValue(dfs, "97") == "Y" {
+		event(POSSRESEND, 0, 0)
+	}
+
+	if msgType == "A" && fieldValue(dfs, "141") == "Y" {
+		// ResetSeqNumFlag: the session starts again from 1
+		*stream = sequenceStream{}
+	}
+
+	newSeqNo, err := strconv.Atoi(fieldValue(dfs, "36"))
+	if msgType == "4" && err == nil && newSeqNo > 0 && fieldValue(dfs, "123") != "Y" {
+		// reset mode: MsgSeqNum <34> is ignored
+		a.reset(session, stream, newSeqNo, event)
+		return
+	}
+
+	switch {
+	case stream.expected == 0 || msgSeqNum == stream.expected:
+	case msgSeqNum > stream.expected:
+		event(GAP, stream.expected, msgSeqNum-1)
+	case possDup:
+		// resent
+	case stream.received.contains(msgSeqNum):
+		event(DUPLICATE, 0, 0)
+	default:
+		event(OUTOFORDER, 0, 0)
+	}
+
+	if msgType == "4" && err == nil && newSeqNo > 0 {
+		event(GAPFILL, msgSeqNum, newSeqNo-1)
+		a.cover(session, msgSeqNum, newSeqNo-1)
+		a.receive(stream, msgSeqNum, newSeqNo-1)
+		return
+	}
+
+	a.cover(session, msgSeqNum, msgSeqNum)
+	a.receive(stream, msgSeqNum, msgSeqNum)
+
+	if msgType == "2" {
+		a.request(target+"->"+sender, index, dfs)
+	}
+}
+
+// Resends the status of every ResendRequest so far
+func (a *SequenceAnalyzer) Resends() []*ResendStatus {
+	statuses := make([]*ResendStatus, 0, len(a.resends))
+	for _, r := range a.resends {
+		r.status.Missing = r.covered.missing(r.status.BeginSeqNo, r.end)
+		r.status.Satisfied = len(r.status.Missing) == 0
+		statuses = append(statuses, r.status)
+	}
+
+	return statuses
+}
+
+// stream the state of a direction of a session
+func (a *SequenceAnalyzer) stream(session string) *sequenceStream {
+	stream, found := a.streams[session]
+	if !found {
+		stream = &sequenceStream{}
+		a.streams[session] = stream
+	}
+
+	return stream
+}
+
+// reset apply a SequenceReset in reset mode: the sequence numbers from the expected one to NewSeqNo-1 are skipped.
+// A NewSeqNo below the expected sequence number is reported and ignored
+func (a *SequenceAnalyzer) reset(session string, stream *sequenceStream, newSeqNo int, event func(kind string, from, to int)) {
+	expected := stream.expected
+	if expected == 0 {
+		expected = 1
+	}
+
+	if newSeqNo < expected {
+		event(BADRESET, newSeqNo, expected-1)
+		return
+	}
+
+	event(SEQUENCERESET, expected, newSeqNo-1)
+	a.cover(session, expected, newSeqNo-1)
+	stream.expected = newSeqNo
+}
+
+// receive record the sequence numbers from-to as received
+func (a *SequenceAnalyzer) receive(stream *sequenceStream, from, to int) {
+	if to < from {
+		// NewSeqNo <36> of a gap fill not above its MsgSeqNum <34>: only the gap fill itself is received
+		to = from
+	}
+
+	stream.received = stream.received.add(from, to)
+
+	if to >= stream.expected {
+		stream.expected = to + 1
+	}
+
+	if to > stream.last {
+		stream.last = to
+	}
+}
+
+// request record a ResendRequest asking session to resend. EndSeqNo <16> is clamped to the last sequence number received from session
+func (a *SequenceAnalyzer) request(session string, index int, dfs DecodedFields) {
+	begin, err1 := strconv.Atoi(fieldValue(dfs, "7"))
+	end, err2 := strconv.Atoi(fieldValue(dfs, "16"))
+	if err1 != nil || err2 != nil {
+		return
+	}
+
+	status := &ResendStatus{Session: session, Index: index, BeginSeqNo: begin, EndSeqNo: end}
+	last := a.stream(session).last
+	_, before43 := contains([]string{"FIX.4.0", "FIX.4.1", "FIX.4.2"}, fieldValue(dfs, BEGINSTRING))
+	if end == 0 || (end == 999999 && before43) || end > last {
+		// infinity: 0, or 999999 before FIX.4.3
+		end = last
+	}
+
+	a.resends = append(a.resends, &resendRequest{status: status, end: end})
+}
+
+// cover record the sequence numbers from-to of session as resent or gap filled for the resend requests
+func (a *SequenceAnalyzer) cover(session string, from, to int) {
+	for _, r := range a.resends {
+		if r.status.Session != session {
+			continue
+		}
+
+		first, last := from, to
+		if first < r.status.BeginSeqNo {
+			first = r.status.BeginSeqNo
+		}
+		if last > r.end {
+			last = r.end
+		}
+
+		r.covered = r.covered.add(first, last)
+	}
+}
+
+// seqRanges sorted ranges of sequence numbers, neither overlapping nor adjacent
+type seqRanges []SeqRange
+
+// add add the range from-to, merged with the ranges it overlaps or touches. Sequence numbers are positive
+func (rs seqRanges) add(from, to int) seqRanges {
+	if from > to {
+		return rs
+	}
+
+	i := sort.Search(len(rs), func(i int) bool { return rs[i].To >= from-1 })
+	j := i
+	for ; j < len(rs) && rs[j].From-1 <= to; j++ {
+		if rs[j].From < from {
+			from = rs[j].From
+		}
+		if rs[j].To > to {
+			to = rs[j].To
+		}
+	}
+
+	result := make(seqRanges, 0, len(rs)-(j-i)+1)
+	result = append(result, rs[:i]...)
+	result = append(result, SeqRange{From: from, To: to})
+	return append(result, rs[j:]...)
+}
+
+// contains whether seq is in one of the ranges
+func (rs seqRanges) contains(seq int) bool {
+	i := sort.Search(len(rs), func(i int) bool { return rs[i].To >= seq })
+	return i < len(rs) && rs[i].From <= seq
+}
+
+// missing the ranges of from-to which are not in rs
+func (rs seqRanges) missing(from, to int) []SeqRange {
+	result := make([]SeqRange, 0)
+	for _, r := range rs {
+		if from > to {
+			break
+		}
+
+		if r.To < from {
+			continue
+		}
+
+		if r.From > to {
+			break
+		}
+
+		if r.From > from {
+			result = append(result, SeqRange{From: from, To: r.From - 1})
+		}
+		from = r.To + 1
+	}
+
+	if from <= to {
+		result = append(result, SeqRange{From: from, To: to})
+	}
+
+	return result
+}
@@ -0,0 +1,123 @@
+package fixdecoder_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	fixdecoder "github.com/ilovelili/FixDecoder"
+)
+
+func TestSequenceAnalyzer(t *testing.T) {
+	messages := []string{
+		"35=A|49=CLIENT|56=BROKER|34=1|",
+		"35=A|49=BROKER|56=CLIENT|34=1|",
+		"35=D|49=CLIENT|56=BROKER|34=2|",
+		"35=D|49=CLIENT|56=BROKER|34=5|",
+		"35=2|49=BROKER|56=CLIENT|34=2|7=3|16=0|",
+		"35=D|49=CLIENT|56=BROKER|34=3|43=Y|",
+		"35=4|49=CLIENT|56=BROKER|34=4|43=Y|123=Y|36=6|",
+		"35=D|49=CLIENT|56=BROKER|34=6|",
+		"35=D|49=CLIENT|56=BROKER|34=6|",
+		"35=D|49=CLIENT|56=BROKER|34=8|97=Y|",
+		"35=D|49=CLIENT|56=BROKER|34=7|",
+		"35=2|49=BROKER|56=CLIENT|34=3|7=7|16=21|",
+		"35=4|49=BROKER|56=CLIENT|34=4|36=10|",
+		"35=4|49=CLIENT|56=BROKER|34=10|123=Y|36=2000000000|",
+	}
+
+	a := fixdecoder.NewSequenceAnalyzer()
+	for _, message := range messages {
+		a.Add(fd.Decode("8=FIX.4.4|9=0|" + message + "10=000|"))
+	}
+
+	events := make([]string, 0)
+	for _, e := range a.Events {
+		events = append(events, fmt.Sprintf("%d %s", e.Index, e))
+	}
+
+	expect := strings.Join([]string{
+		"3 gap CLIENT->BROKER 3-4 (MsgSeqNum 5)",
+		"5 possdup CLIENT->BROKER (MsgSeqNum 3)",
+		"6 possdup CLIENT->BROKER (MsgSeqNum 4)",
+		"6 gap-fill CLIENT->BROKER 4-5 (MsgSeqNum 4)",
+		"8 duplicate CLIENT->BROKER (MsgSeqNum 6)",
+		"9 possresend CLIENT->BROKER (MsgSeqNum 8)",
+		"9 gap CLIENT->BROKER 7-7 (MsgSeqNum 8)",
+		"10 out-of-order CLIENT->BROKER (MsgSeqNum 7)",
+		"12 sequence-reset BROKER->CLIENT 4-9 (MsgSeqNum 4)",
+		"13 gap CLIENT->BROKER 9-9 (MsgSeqNum 10)",
+		"13 gap-fill CLIENT->BROKER 10-1999999999 (MsgSeqNum 10)",
+	}, "\n")
+
+	if actual := strings.Join(events, "\n"); actual != expect {
+		t.Errorf("expect %s, actual %s", expect, actual)
+	}
+
+	resends := a.Resends()
+	if len(resends) != 2 {
+		t.Fatalf("expect 2 resend requests, actual %d", len(resends))
+	}
+
+	if r := resends[0]; r.Session != "CLIENT->BROKER" || r.BeginSeqNo != 3 || r.EndSeqNo != 0 || !r.Satisfied {
+		t.Errorf("expect satisfied resend request, actual %+v", r)
+	}
+
+	if r := resends[1]; r.Satisfied || fmt.Sprint(r.Missing) != "[7-8]" {
+		t.Errorf("expect unsatisfied resend request missing 7-8, actual %+v", r)
+	}
+}
+
+func TestSequenceAnalyzer_Infinity(t *testing.T) {
+	a := fixdecoder.NewSequenceAnalyzer()
+	for _, message := range []string{
+		"8=FIX.4.2|9=0|35=D|49=CLIENT|56=BROKER|34=1|10=000|",
+		"8=FIX.4.2|9=0|35=D|49=CLIENT|56=BROKER|34=4|10=000|",
+		"8=FIX.4.2|9=0|35=2|49=BROKER|56=CLIENT|34=1|7=2|16=999999|10=000|",
+		"8=FIX.4.2|9=0|35=D|49=CLIENT|56=BROKER|34=2|43=Y|10=000|",
+		"8=FIX.4.2|9=0|35=D|49=CLIENT|56=BROKER|34=5|10=000|",
+	} {
+		a.Add(fd.Decode(message))
+	}
+
+	// 999999 stands for infinity before FIX.4.3: up to 4, the last MsgSeqNum when the request was sent
+	if r := a.Resends()[0]; r.EndSeqNo != 999999 || r.Satisfied || fmt.Sprint(r.Missing) != "[3-4]" {
+		t.Errorf("expect resend request missing 3-4, actual %+v", r)
+	}
+}
+
+func TestSequenceAnalyzer_Reset(t *testing.T) {
+	a := fixdecoder.NewSequenceAnalyzer()
+	for _, message := range []string{
+		"35=D|49=CLIENT|56=BROKER|34=1|",
+		"35=D|49=CLIENT|56=BROKER|34=2|",
+		"35=D|49=CLIENT|56=BROKER|34=5|",
+		"35=2|49=BROKER|56=CLIENT|34=1|7=1|16=0|",
+		"35=4|49=CLIENT|56=BROKER|34=3|36=8|",
+		"35=4|49=CLIENT|56=BROKER|34=3|36=4|",
+		"35=D|49=CLIENT|56=BROKER|34=8|",
+	} {
+		a.Add(fd.Decode("8=FIX.4.4|9=0|" + message + "10=000|"))
+	}
+
+	events := make([]string, 0)
+	for _, e := range a.Events {
+		events = append(events, fmt.Sprintf("%d %s", e.Index, e))
+	}
+
+	// the MsgSeqNum of a reset is ignored, and a reset below the expected MsgSeqNum is reported
+	expect := strings.Join([]string{
+		"2 gap CLIENT->BROKER 3-4 (MsgSeqNum 5)",
+		"4 sequence-reset CLIENT->BROKER 6-7 (MsgSeqNum 3)",
+		"5 bad-reset CLIENT->BROKER 4-7 (MsgSeqNum 3)",
+	}, "\n")
+
+	if actual := strings.Join(events, "\n"); actual != expect {
+		t.Errorf("expect %s, actual %s", expect, actual)
+	}
+
+	// the reset only skips 6-7, after the last sequence number when the request was sent
+	if r := a.Resends()[0]; r.Satisfied || fmt.Sprint(r.Missing) != "[1-5]" {
+		t.Errorf("expect resend request missing 1-5, actual %+v", r)
+	}
+}
@@ -0,0 +1,232 @@
+package fixdecoder
+
+import (
+	"bytes"
+	"errors"
+	"io"
+)
+
+const (
+	// streamReadSize number of bytes read from the stream at once
+	streamReadSize = 64 * 1024
+	// maxMessageSize messages larger than this are considered corrupted
+	maxMessageSize = 4 * 1024 * 1024
+)
+
+// beginStringPrefix start of every FIX message, FIXT.1.1 included
+var beginStringPrefix = []byte("8=FIX")
+
+// StreamDecoder decode the FIX messages of a stream (engine log, raw TCP capture...) one by one. Messages are framed by BodyLength <9>
+// and CheckSum <10>, bytes between messages are skipped, and decoding resynchronizes on the next 8=FIX after a corrupted message
+type StreamDecoder struct {
+	decoder *FixDecoder
+	reader  io.Reader
+	buffer  []byte
+	offset  int // Stream offset of buffer[0]
+	eof     bool
+	err     error
+}
+
+// NewStreamDecoder new stream decoder instance. Options are the ones of NewFixDecoder
+func NewStreamDecoder(r io.Reader, options ...Option) *StreamDecoder {
+	return &StreamDecoder{
+		decoder: NewFixDecoder(options...),
+		reader:  r,
+		buffer:  make([]byte, 0, streamReadSize),
+	}
+}
+
+// Next decode the next message of the stream; its Offset is the byte offset of its BeginString <8> in the stream.
+// It returns io.EOF at the end of the stream, or the read error of the underlying reader. Any other error concerns one corrupted message,
+// its offset is relative to the stream too, and Next can be called again to go on with the following messages
+func (s *StreamDecoder) Next() (*Message, error) {
+	for {
+		start := bytes.Index(s.buffer, beginStringPrefix)
+		if start < 0 {
+			// keep what may be the beginning of the prefix
+			if keep := len(beginStringPrefix) - 1; len(s.buffer) > keep {
+				s.discard(len(s.buffer) - keep)
+			}
+
+			if !s.fill() {
+				return nil, s.endError()
+			}
+			continue
+		}
+
+		s.discard(start)
+		end, err := frameMessage(s.buffer, s.decoder.delimiter, s.eof || len(s.buffer) >= maxMessageSize)
+		if err == errNeedMore {
+			if !s.fill() {
+				if s.err != nil {
+					return nil, s.err
+				}
+				// at the end of the stream: frame once more, knowing no more data will come
+				s.eof = true
+			}
+			continue
+		}
+
+		offset := s.offset
+		if err != nil {
+			// skip this BeginString to resynchronize on the next one
+			s.discard(1)
+			return nil, withOffset(err, offset)
+		}
+
+		raw := string(s.buffer[:end])
+		s.discard(end)
+
+		msg, err := s.decoder.DecodeE(raw)
+		if err != nil {
+			return nil, withOffset(err, offset)
+		}
+
+		msg.Offset = offset
+		return msg, nil
+	}
+}
+
+// fill read more data into the buffer. It returns false when nothing more can be read
+func (s *StreamDecoder) fill() bool {
+	if s.eof || s.err != nil {
+		return false
+	}
+
+	if cap(s.buffer)-len(s.buffer) < streamReadSize {
+		buffer := make([]byte, len(s.buffer), 2*cap(s.buffer)+streamReadSize)
+		copy(buffer, s.buffer)
+		s.buffer = buffer
+	}
+
+	n, err := s.reader.Read(s.buffer[len(s.buffer) : len(s.buffer)+streamReadSize])
+	s.buffer = s.buffer[:len(s.buffer)+n]
+
+	if err == io.EOF {
+		s.eof = true
+		return n > 0
+	} else if err != nil {
+		s.err = err
+		return n > 0
+	}
+
+	return true
+}
+
+// discard drop the first n bytes of the buffer
+func (s *StreamDecoder) discard(n int) {
+	s.offset += n
+	s.buffer = s.buffer[:copy(s.buffer, s.buffer[n:])]
+}
+
+// endError error returned once the stream is exhausted
+func (s *StreamDecoder) endError() error {
+	if s.err != nil {
+		return s.err
+	}
+
+	return io.EOF
+}
+
+// errNeedMore the buffer does not hold a whole message yet
+var errNeedMore = errors.New("fixdecoder: need more data")
+
+// frameMessage find the end of the message starting at buf[0] with 8=FIX. The message normally ends BodyLength <9> bytes after the BodyLength field,
+// with the CheckSum <10> field. If it does not, the first CheckSum field before the next BeginString ends it.
+// The delimiter is detected from the message if empty. final tells no more data will be appended to buf; otherwise errNeedMore is returned when buf is too short
+func frameMessage(buf []byte, delimiter string, final bool) (int, error) {
+	needMore := func(offset int) (int, error) {
+		if final {
+			return 0, &ErrTruncated{Offset: offset}
+		}
+		return 0, errNeedMore
+	}
+
+	// 8={{BeginString}}<delimiter>
+	p := len("8=")
+	for p < len(buf) && isBeginStringByte(buf[p]) {
+		p++
+	}
+
+	if delimiter == "" {
+		if len(buf) < p+len(LITERALSOH) && !final {
+			return 0, errNeedMore
+		}
+
+		if delimiter = DetectDelimiter(buf); delimiter == "" {
+			return needMore(p)
+		}
+	}
+
+	if len(buf) < p+len(delimiter) {
+		return needMore(len(buf))
+	}
+
+	if delimiterAt(buf, p, delimiter) == 0 {
+		return 0, &ErrMalformedField{Offset: 0, Raw: string(buf[:fieldEnd(buf, 0, delimiter)])}
+	}
+	p += len(delimiter)
+
+	// 9={{BodyLength}}<delimiter>
+	if len(buf) < p+len("9=") {
+		return needMore(len(buf))
+	}
+
+	if buf[p] != '9' || buf[p+1] != '=' {
+		return 0, &ErrMalformedField{Offset: p, Raw: string(buf[p:fieldEnd(buf, p, delimiter)])}
+	}
+
+	length := 0
+	q := p + len("9=")
+	for ; q < len(buf) && buf[q] >= '0' && buf[q] <= '9'; q++ {
+		length = length*10 + int(buf[q]-'0')
+		if length > maxMessageSize {
+			return 0, &ErrMalformedField{Offset: p, Raw: string(buf[p:fieldEnd(buf, p, delimiter)])}
+		}
+	}
+
+	if len(buf) < q+len(delimiter) {
+		return needMore(len(buf))
+	}
+
+	if q == p+len("9=") || delimiterAt(buf, q, delimiter) == 0 {
+		return 0, &ErrMalformedField{Offset: p, Raw: string(buf[p:fieldEnd(buf, p, delimiter)])}
+	}
+
+	// 10={{CheckSum}}<delimiter> right after the body
+	bodyEnd := q + len(delimiter) + length
+	if len(buf) >= bodyEnd+len("10=") && bytes.HasPrefix(buf[bodyEnd:], []byte("10=")) {
+		if end, size := nextDelimiter(buf, bodyEnd+len("10="), delimiter); size > 0 {
+			return end + size, nil
+		}
+	}
+
+	if len(buf) < bodyEnd+len("10=000")+1 && !final {
+		return 0, errNeedMore
+	}
+
+	// wrong BodyLength: the message ends with the first CheckSum before the next BeginString
+	region := buf[q:]
+	if next := bytes.Index(region, beginStringPrefix); next >= 0 {
+		region = region[:next]
+	}
+
+	if checksum := bytes.Index(region, []byte(delimiter+"10=")); checksum >= 0 {
+		if end, size := nextDelimiter(buf[:q+len(region)], q+checksum+len(delimiter)+len("10="), delimiter); size > 0 {
+			return end + size, nil
+		}
+	}
+
+	if q+len(region) < len(buf) {
+		// another message starts before this one is complete
+		return 0, &ErrTruncated{Offset: q + len(region)}
+	}
+
+	return needMore(len(buf))
+}
+
+// fieldEnd the offset of the delimiter ending the field starting at buf[start], or len(buf)
+func fieldEnd(buf []byte, start int, delimiter string) int {
+	end, _ := nextDelimiter(buf, start, delimiter)
+	return end
+}
@@ -0,0 +1,75 @@
+package fixdecoder_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	fixdecoder "github.com/ilovelili/FixDecoder"
+)
+
+func TestStreamDecoder(t *testing.T) {
+	corrupted := "8=FIX.4.4\x019=74\x0135=2\x01"
+	wrongbodylength := strings.Replace(validfixmessage, "9=74", "9=70", 1)
+	junk := "\n20180126-07:40:00 junk\n"
+	stream := validfixmessage + junk + corrupted + wrongbodylength + validfixmessage
+
+	// read byte by byte to exercise framing across reads
+	sd := fixdecoder.NewStreamDecoder(iotest.OneByteReader(strings.NewReader(stream)))
+
+	msg, err := sd.Next()
+	if err != nil || msg.Offset != 0 || len(msg.Fields) != 10 {
+		t.Fatalf("expect first message at offset 0, actual %v %v", msg, err)
+	}
+
+	corruptedOffset := len(validfixmessage) + len(junk)
+	var truncated *fixdecoder.ErrTruncated
+	if _, err = sd.Next(); !errors.As(err, &truncated) || truncated.Offset < corruptedOffset {
+		t.Fatalf("expect truncated message after offset %d, actual %v", corruptedOffset, err)
+	}
+
+	msg, err = sd.Next()
+	if expect := corruptedOffset + len(corrupted); err != nil || msg.Offset != expect || len(msg.Fields) != 10 {
+		t.Fatalf("expect message with wrong body length at offset %d, actual %v %v", expect, msg, err)
+	}
+
+	msg, err = sd.Next()
+	if expect := len(stream) - len(validfixmessage); err != nil || msg.Offset != expect {
+		t.Fatalf("expect last message at offset %d, actual %v %v", expect, msg, err)
+	}
+
+	if _, err = sd.Next(); err != io.EOF {
+		t.Errorf("expect EOF, actual %v", err)
+	}
+}
+
+func TestStreamDecoder_Delimiter(t *testing.T) {
+	message := strings.Replace(validfixmessage, "\x01", fixdecoder.CARETSOH, -1)
+	sd := fixdecoder.NewStreamDecoder(iotest.OneByteReader(strings.NewReader(message + "\n" + message + "\n")))
+
+	for i := 0; i < 2; i++ {
+		msg, err := sd.Next()
+		if expect := i * (len(message) + 1); err != nil || msg.Offset != expect || len(msg.Fields) != 10 {
+			t.Fatalf("expect message at offset %d, actual %v %v", expect, msg, err)
+		}
+	}
+
+	if _, err := sd.Next(); err != io.EOF {
+		t.Errorf("expect EOF, actual %v", err)
+	}
+}
+
+func TestStreamDecoder_Truncated(t *testing.T) {
+	sd := fixdecoder.NewStreamDecoder(strings.NewReader(validfixmessage[:40]))
+
+	var truncated *fixdecoder.ErrTruncated
+	if _, err := sd.Next(); !errors.As(err, &truncated) {
+		t.Fatalf("expect truncated message, actual %v", err)
+	}
+
+	if _, err := sd.Next(); err != io.EOF {
+		t.Errorf("expect EOF, actual %v", err)
+	}
+}
@@ -0,0 +1,244 @@
+package fixdecoder
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// validatorRegistry validators by name, in registration order
+type validatorRegistry struct {
+	mutex      sync.RWMutex
+	names      []string
+	validators map[string]Validator
+	enabled    map[string]bool
+}
+
+// registeredValidator a validator of the registry
+type registeredValidator struct {
+	name      string
+	validator Validator
+	enabled   bool
+}
+
+// snapshot the registered validators, in registration order. Validators are run on a snapshot, out of the lock, so that they may use the registry
+func (r *validatorRegistry) snapshot() []registeredValidator {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	result := make([]registeredValidator, 0, len(r.names))
+	for _, name := range r.names {
+		result = append(result, registeredValidator{name: name, validator: r.validators[name], enabled: r.enabled[name]})
+	}
+
+	return result
+}
+
+// registry the validators available to profiles
+var registry = &validatorRegistry{validators: make(map[string]Validator), enabled: make(map[string]bool)}
+
+func init() {
+	RegisterValidator("body-length", BodyLengthValidator{}, true)
+	RegisterValidator("checksum", CheckSumValidator{}, true)
+	RegisterValidator("group-count", GroupCountValidator{}, true)
+	RegisterValidator("data-length", DataLengthValidator{}, true)
+	RegisterValidator("message", &MessageValidator{}, false)
+	RegisterValidator("enum", &EnumValidator{}, false)
+	RegisterValidator("type", TypeValidator{}, false)
+	RegisterValidator("field-order", &FieldOrderValidator{}, false)
+	RegisterValidator("duplicate-field", DuplicateFieldValidator{}, false)
+}
+
+// RegisterValidator register a validator by name, so that profiles can enable, disable and configure it. enabled tells whether it runs
+// when no profile says otherwise. Registering an existing name replaces the validator, built-in ones included:
+// body-length, checksum, group-count, data-length (all enabled), message, enum, type, field-order and duplicate-field (disabled)
+func RegisterValidator(name string, v Validator, enabled bool) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	if _, found := registry.validators[name]; !found {
+		registry.names = append(registry.names, name)
+	}
+
+	registry.validators[name] = v
+	registry.enabled[name] = enabled
+}
+
+// UnregisterValidator remove a registered validator. Profiles naming it can no longer be loaded, and the ones already loaded skip it
+func UnregisterValidator(name string) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	if _, found := registry.validators[name]; !found {
+		return
+	}
+
+	delete(registry.validators, name)
+	delete(registry.enabled, name)
+	index, _ := contains(registry.names, name)
+	registry.names = append(registry.names[:index:index], registry.names[index+1:]...)
+}
+
+// RegisteredValidators the names of the registered validators, in registration order
+func RegisteredValidators() []string {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	return append([]string(nil), registry.names...)
+}
+
+// ConfigurableValidator a validator which profiles can configure: Configure returns a new validator set up with the options of a rule,
+// the registered one is left untouched. The enum, message and field-order validators are configurable
+type ConfigurableValidator interface {
+	Validator
+	Configure(options json.RawMessage) (Validator, error)
+}
+
+// ValidationProfile the validation rules of a counterparty, e.g. one whose heartbeats carry a wrong checksum and which sends
+// lower case HandlInst <21> values:
+//
+//	{
+//	  "name": "VENUEX",
+//	  "rules": [
+//	    {"validator": "checksum", "msgTypes": ["0"], "severity": "warning"},
+//	    {"validator": "message", "enabled": true, "options": {"dictionary": "venuex/FIX44.xml"}},
+//	    {"validator": "enum", "enabled": true, "options": {"extensions": {"21": [{"from": "a", "to": "z"}]}}}
+//	  ]
+//	}
+type ValidationProfile struct {
+	Name  string
+	Rules []*ValidationRule
+}
+
+// ValidationRule enable, disable, configure or change the severity of a registered validator, for all messages or for some message types only.
+// When several rules match a message, the later ones win
+type ValidationRule struct {
+	Validator string
+	MsgTypes  []string        // MsgType <35> values the rule applies to, all if empty
+	Enabled   *bool           // Unchanged if nil
+	Severity  string          // ERROR or WARNING, unchanged if empty
+	Options   json.RawMessage // Options of a ConfigurableValidator, see Instance
+	Instance  Validator       `json:"-"` // Validator run instead of the registered one if not nil, built from Options by LoadValidationProfile
+}
+
+// LoadValidationProfile load a JSON validation profile. Its validators must be registered
+func LoadValidationProfile(r io.Reader) (*ValidationProfile, error) {
+	profile := &ValidationProfile{}
+	if err := json.NewDecoder(r).Decode(profile); err != nil {
+		return nil, fmt.Errorf("fixdecoder: invalid validation profile: %v", err)
+	}
+
+	names := RegisteredValidators()
+	for _, rule := range profile.Rules {
+		if _, found := contains(names, rule.Validator); !found {
+			return nil, fmt.Errorf("fixdecoder: unknown validator %q in validation profile %s", rule.Validator, profile.Name)
+		}
+
+		if rule.Severity != "" && rule.Severity != ERROR && rule.Severity != WARNING {
+			return nil, fmt.Errorf("fixdecoder: unknown severity %q in validation profile %s", rule.Severity, profile.Name)
+		}
+
+		if len(rule.Options) == 0 {
+			continue
+		}
+
+		v, configurable := registeredValidatorByName(rule.Validator).(ConfigurableValidator)
+		if !configurable {
+			return nil, fmt.Errorf("fixdecoder: validator %q has no options in validation profile %s", rule.Validator, profile.Name)
+		}
+
+		instance, err := v.Configure(rule.Options)
+		if err != nil {
+			return nil, fmt.Errorf("fixdecoder: invalid options of validator %q in validation profile %s: %v", rule.Validator, profile.Name, err)
+		}
+		rule.Instance = instance
+	}
+
+	return profile, nil
+}
+
+// registeredValidatorByName the registered validator of a name, nil if not found
+func registeredValidatorByName(name string) Validator {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	return registry.validators[name]
+}
+
+// LoadValidationProfileFile load a JSON validation profile file
+func LoadValidationProfileFile(path string) (*ValidationProfile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return LoadValidationProfile(file)
+}
+
+// Validate run the registered validators enabled for the message, and apply the severities of the profile. A nil profile runs the validators enabled by default
+func (p *ValidationProfile) Validate(dfs DecodedFields) []*ValidationIssue {
+	return p.validate(dfs, nil)
+}
+
+// validate run the validators of the profile. Dictionary validators check the message against dictionary, the built-in one if nil
+func (p *ValidationProfile) validate(dfs DecodedFields, dictionary *Dictionary) []*ValidationIssue {
+	msgType := fieldValue(dfs, MSGTYPE)
+	issues := make([]*ValidationIssue, 0)
+	for _, registered := range registry.snapshot() {
+		v, enabled, severity := registered.validator, registered.enabled, ""
+		if p != nil {
+			for _, rule := range p.Rules {
+				if !rule.matches(registered.name, msgType) {
+					continue
+				}
+
+				if rule.Enabled != nil {
+					enabled = *rule.Enabled
+				}
+				if rule.Severity != "" {
+					severity = rule.Severity
+				}
+				if rule.Instance != nil {
+					v = rule.Instance
+				}
+			}
+		}
+
+		if !enabled {
+			continue
+		}
+
+		var found []*ValidationIssue
+		if dv, ok := v.(DictionaryValidator); ok {
+			found = dv.ValidateWith(dfs, dictionary)
+		} else {
+			found = v.Validate(dfs)
+		}
+
+		for _, issue := range found {
+			if severity != "" {
+				issue.Severity = severity
+			}
+			issues = append(issues, issue)
+		}
+	}
+
+	return issues
+}
+
+// matches whether the rule applies to a validator for a message type
+func (r *ValidationRule) matches(validator, msgType string) bool {
+	if r.Validator != validator {
+		return false
+	}
+
+	if len(r.MsgTypes) == 0 {
+		return true
+	}
+
+	_, found := contains(r.MsgTypes, msgType)
+	return found
+}
@@ -0,0 +1,154 @@
+package fixdecoder_test
+
+import (
+	"strings"
+	"testing"
+
+	fixdecoder "github.com/ilovelili/FixDecoder"
+)
+
+// houseRule a validator rejecting orders without Account <1>
+type houseRule struct{}
+
+func (houseRule) Validate(dfs fixdecoder.DecodedFields) []*fixdecoder.ValidationIssue {
+	for _, df := range dfs {
+		if df.FieldID == "1" {
+			return nil
+		}
+	}
+
+	return []*fixdecoder.ValidationIssue{{Tag: 1, Severity: fixdecoder.ERROR, Code: "missing-account", Message: "Account is missing"}}
+}
+
+func TestValidationProfile(t *testing.T) {
+	fixdecoder.RegisterValidator("house-account", houseRule{}, false)
+	t.Cleanup(func() { fixdecoder.UnregisterValidator("house-account") })
+
+	profile, err := fixdecoder.LoadValidationProfile(strings.NewReader(`{
+		"name": "VENUEX",
+		"rules": [
+			{"validator": "checksum", "msgTypes": ["0"], "severity": "warning"},
+			{"validator": "house-account", "msgTypes": ["D"], "enabled": true},
+			{"validator": "group-count", "enabled": false}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("expect no error, actual %v", err)
+	}
+
+	decoder := fixdecoder.NewFixDecoder(fixdecoder.WithValidationProfile(profile))
+
+	heartbeat := fd.Decode("8=FIX.4.4|9=5|35=0|10=000|")
+	if issues := decoder.Validate(heartbeat); len(issues) != 1 || issues[0].Code != fixdecoder.INVALIDCHECKSUM || issues[0].Severity != fixdecoder.WARNING {
+		t.Errorf("expect checksum warning, actual %v", issues)
+	}
+
+	order := fd.Decode("8=FIX.4.4|9=32|35=D|453=2|448=BRKR|447=D|452=1|10=000|")
+	codes := make([]string, 0)
+	for _, issue := range decoder.Validate(order) {
+		codes = append(codes, issue.Severity+" "+issue.Code)
+	}
+
+	if expect, actual := "error invalid-checksum,error missing-account", strings.Join(codes, ","); actual != expect {
+		t.Errorf("expect %s, actual %s", expect, actual)
+	}
+
+	if issues := order.Validate(); len(issues) != 2 {
+		t.Errorf("expect checksum and group count issues without profile, actual %v", issues)
+	}
+
+	if _, err := fixdecoder.LoadValidationProfile(strings.NewReader(`{"rules": [{"validator": "unknown"}]}`)); err == nil {
+		t.Error("expect unknown validator error")
+	}
+
+	if _, err := fixdecoder.LoadValidationProfile(strings.NewReader(`{"rules": [{"validator": "checksum", "severity": "fatal"}]}`)); err == nil {
+		t.Error("expect unknown severity error")
+	}
+}
+
+func TestFixDecoder_Validate_Dictionary(t *testing.T) {
+	dictionary, err := fixdecoder.LoadQuickFIXDictionary(strings.NewReader(quickfixdictionary))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enabled := true
+	profile := &fixdecoder.ValidationProfile{Name: "VENUEX", Rules: []*fixdecoder.ValidationRule{
+		{Validator: "checksum", Enabled: new(bool)},
+		{Validator: "body-length", Enabled: new(bool)},
+		{Validator: "message", Enabled: &enabled},
+		{Validator: "enum", Enabled: &enabled},
+	}}
+
+	// VenueOrderFlag <5001> is only defined by the QuickFIX dictionary of the decoder
+	decoder := fixdecoder.NewFixDecoder(fixdecoder.WithDictionary(dictionary), fixdecoder.WithValidationProfile(profile))
+	if issues := decoder.Validate(decoder.Decode("8=FIX.4.4|9=0|35=D|11=ORD1|54=2|5001=Y|10=000|")); len(issues) != 0 {
+		t.Errorf("expect valid, actual %v", issues)
+	}
+
+	issues := decoder.Validate(decoder.Decode("8=FIX.4.4|9=0|35=D|11=ORD1|54=2|5001=Z|10=000|"))
+	if expect, actual := "5001", issueTags(issues, fixdecoder.INVALIDENUMVALUE); actual != expect || len(issues) != 1 {
+		t.Errorf("expect invalid %s, actual %v", expect, issues)
+	}
+
+	// without the dictionary of the decoder, the built-in one does not know VenueOrderFlag
+	if expect, actual := "5001", issueTags(profile.Validate(decoder.Decode("8=FIX.4.4|9=0|35=D|11=ORD1|54=2|5001=Y|10=000|")), fixdecoder.FIELDNOTALLOWED); actual != expect {
+		t.Errorf("expect not allowed %s, actual %s", expect, actual)
+	}
+}
+
+// reentrantRule a validator registering another one while it runs
+type reentrantRule struct{}
+
+func (reentrantRule) Validate(dfs fixdecoder.DecodedFields) []*fixdecoder.ValidationIssue {
+	fixdecoder.RegisterValidator("house-reentrant-helper", houseRule{}, false)
+	return nil
+}
+
+func TestValidationProfile_Reentrant(t *testing.T) {
+	fixdecoder.RegisterValidator("house-reentrant", reentrantRule{}, true)
+	t.Cleanup(func() {
+		fixdecoder.UnregisterValidator("house-reentrant")
+		fixdecoder.UnregisterValidator("house-reentrant-helper")
+	})
+
+	if issues := fd.Validate(fd.Decode(validfixmessage)); len(issues) != 0 {
+		t.Errorf("expect valid, actual %v", issues)
+	}
+
+	if expect, actual := "house-reentrant-helper", strings.Join(fixdecoder.RegisteredValidators(), ","); !strings.HasSuffix(actual, expect) {
+		t.Errorf("expect %s, actual %s", expect, actual)
+	}
+}
+
+func TestValidationProfile_Options(t *testing.T) {
+	profile, err := fixdecoder.LoadValidationProfile(strings.NewReader(`{
+		"name": "VENUEX",
+		"rules": [
+			{"validator": "enum", "enabled": true, "options": {"extensions": {"21": [{"from": "a", "to": "z"}]}}}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("expect no error, actual %v", err)
+	}
+
+	order := fd.Decode("8=FIX.4.4|9=0|35=D|21=x|54=q|10=000|")
+	if expect, actual := "54", issueTags(fixdecoder.NewFixDecoder(fixdecoder.WithValidationProfile(profile)).Validate(order), fixdecoder.INVALIDENUMVALUE); actual != expect {
+		t.Errorf("expect invalid %s, actual %s", expect, actual)
+	}
+
+	// the registered enum validator is left untouched
+	if expect, actual := "21,54", issueTags((&fixdecoder.EnumValidator{}).Validate(order), fixdecoder.INVALIDENUMVALUE); actual != expect {
+		t.Errorf("expect invalid %s, actual %s", expect, actual)
+	}
+
+	for _, rules := range []string{
+		`[{"validator": "checksum", "options": {"strict": true}}]`,
+		`[{"validator": "enum", "options": {"extensions": 1}}]`,
+		`[{"validator": "message", "options": {"dictionary": "missing.xml"}}]`,
+	} {
+		if _, err := fixdecoder.LoadValidationProfile(strings.NewReader(`{"rules": ` + rules + `}`)); err == nil {
+			t.Errorf("expect options error for %s", rules)
+		}
+	}
+}
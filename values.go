@@ -0,0 +1,441 @@
+package fixdecoder
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layouts of the FIX date and time types
+const (
+	UTCTIMESTAMPLAYOUT = "20060102-15:04:05" // UTCTIMESTAMP, TZTIMESTAMP
+	UTCTIMEONLYLAYOUT  = "15:04:05"          // UTCTIMEONLY, TZTIMEONLY, LOCALMKTTIME
+	DATELAYOUT         = "20060102"          // LOCALMKTDATE, UTCDATEONLY
+)
+
+// Decimal an exact decimal number, e.g. a PRICE, QTY or AMT value: unscaled × 10^-scale, so that 12.50 keeps its two decimals
+type Decimal struct {
+	unscaled *big.Int
+	scale    int
+}
+
+// ParseDecimal parse a FIX decimal value: an optional minus sign, digits and an optional decimal point, e.g. "-12.50". No exponent, no plus sign
+func ParseDecimal(s string) (Decimal, error) {
+	digits := strings.TrimPrefix(s, "-")
+	point := strings.IndexByte(digits, '.')
+
+	scale := 0
+	if point >= 0 {
+		scale = len(digits) - point - 1
+		digits = digits[:point] + digits[point+1:]
+	}
+
+	if !isDigits(digits) {
+		return Decimal{}, fmt.Errorf("fixdecoder: invalid decimal %q", s)
+	}
+
+	unscaled, _ := new(big.Int).SetString(digits, 10)
+	if strings.HasPrefix(s, "-") {
+		unscaled.Neg(unscaled)
+	}
+
+	return Decimal{unscaled: unscaled, scale: scale}, nil
+}
+
+// Unscaled the decimal without its decimal point, e.g. 1250 for 12.50
+func (d Decimal) Unscaled() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+
+	return new(big.Int).Set(d.unscaled)
+}
+
+// Scale the number of decimals, e.g. 2 for 12.50
+func (d Decimal) Scale() int {
+	return d.scale
+}
+
+// Cmp compare with another decimal: -1 if lower, 0 if equal, 1 if greater. 12.5 and 12.50 are equal
+func (d Decimal) Cmp(other Decimal) int {
+	a, b, _ := d.align(other)
+	return a.Cmp(b)
+}
+
+// Add the exact sum of two decimals, with the larger scale of the two, e.g. 0.3 for 0.1 + 0.2
+func (d Decimal) Add(other Decimal) Decimal {
+	a, b, scale := d.align(other)
+	return Decimal{unscaled: a.Add(a, b), scale: scale}
+}
+
+// Sign -1 if negative, 0 if zero, 1 if positive
+func (d Decimal) Sign() int {
+	if d.unscaled == nil {
+		return 0
+	}
+
+	return d.unscaled.Sign()
+}
+
+// align the unscaled values of two decimals at the larger scale of the two
+func (d Decimal) align(other Decimal) (*big.Int, *big.Int, int) {
+	a, b := d.Unscaled(), other.Unscaled()
+	if d.scale < other.scale {
+		return a.Mul(a, pow10(other.scale-d.scale)), b, other.scale
+	}
+
+	return a, b.Mul(b, pow10(d.scale-other.scale)), d.scale
+}
+
+// Float64 the nearest float64, for display or approximate computations
+func (d Decimal) Float64() float64 {
+	f, _ := new(big.Rat).SetFrac(d.Unscaled(), pow10(d.scale)).Float64()
+	return f
+}
+
+// String the decimal with its scale, e.g. "12.50"
+func (d Decimal) String() string {
+	unscaled := d.Unscaled()
+	digits := new(big.Int).Abs(unscaled).String()
+	if d.scale > 0 {
+		if len(digits) <= d.scale {
+			digits = strings.Repeat("0", d.scale-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-d.scale] + "." + digits[len(digits)-d.scale:]
+	}
+
+	if unscaled.Sign() < 0 {
+		return "-" + digits
+	}
+
+	return digits
+}
+
+// pow10 10^n
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// MonthYear a MONTHYEAR value: a month, with an optional day (YYYYMMDD) or week (YYYYMMwN) of the month
+type MonthYear struct {
+	Year  int
+	Month time.Month
+	Day   int // 0 if not set
+	Week  int // 1 to 5, 0 if not set
+}
+
+// String the value in the FIX format, e.g. "202603", "20260315" or "202603w2"
+func (m MonthYear) String() string {
+	s := fmt.Sprintf("%04d%02d", m.Year, int(m.Month))
+	switch {
+	case m.Day > 0:
+		s += fmt.Sprintf("%02d", m.Day)
+	case m.Week > 0:
+		s += fmt.Sprintf("w%d", m.Week)
+	}
+
+	return s
+}
+
+// ParseMonthYear parse a MONTHYEAR value: YYYYMM, YYYYMMDD or YYYYMMwN
+func ParseMonthYear(s string) (MonthYear, error) {
+	invalid := fmt.Errorf("fixdecoder: invalid month year %q", s)
+	if len(s) < 6 || !isDigits(s[:6]) {
+		return MonthYear{}, invalid
+	}
+
+	m := MonthYear{Year: atoi(s[:4]), Month: time.Month(atoi(s[4:6]))}
+	if m.Month < time.January || m.Month > time.December {
+		return MonthYear{}, invalid
+	}
+
+	switch suffix := s[6:]; {
+	case suffix == "":
+	case len(suffix) == 2 && isDigits(suffix):
+		m.Day = atoi(suffix)
+		if _, err := time.Parse(DATELAYOUT, s); err != nil {
+			return MonthYear{}, invalid
+		}
+	case len(suffix) == 2 && suffix[0] == 'w' && suffix[1] >= '1' && suffix[1] <= '5':
+		m.Week = int(suffix[1] - '0')
+	default:
+		return MonthYear{}, invalid
+	}
+
+	return m, nil
+}
+
+// Int the value of an INT, SEQNUM, LENGTH or NUMINGROUP field: an optional minus sign and digits
+func (df *DecodedField) Int() (int, error) {
+	digits := strings.TrimPrefix(df.Value, "-")
+	n, err := strconv.Atoi(df.Value)
+	if err != nil || !isDigits(digits) {
+		return 0, df.invalid("INT")
+	}
+
+	return n, nil
+}
+
+// Decimal the value of a PRICE, QTY, AMT, FLOAT, PERCENTAGE or PRICEOFFSET field, exactly
+func (df *DecodedField) Decimal() (Decimal, error) {
+	d, err := ParseDecimal(df.Value)
+	if err != nil {
+		return Decimal{}, df.invalid("FLOAT")
+	}
+
+	return d, nil
+}
+
+// Bool the value of a BOOLEAN field: Y or N
+func (df *DecodedField) Bool() (bool, error) {
+	switch df.Value {
+	case "Y":
+		return true, nil
+	case "N":
+		return false, nil
+	}
+
+	return false, df.invalid("BOOLEAN")
+}
+
+// Time the value of a UTCTIMESTAMP field, with a millisecond, microsecond, nanosecond or picosecond fraction, e.g. 20260301-10:00:00.123456.
+// UTCTIMEONLY and LOCALMKTTIME fields are parsed as a time of day, on January 1 of year 0. TZTIMESTAMP and TZTIMEONLY fields may omit
+// the seconds and end with their offset from UTC: Z, +hh or +hh:mm, e.g. 20260301-10:00+01 (UTC without offset). Picoseconds are truncated
+func (df *DecodedField) Time() (time.Time, error) {
+	fieldType := "UTCTIMESTAMP"
+	if df.Field != nil && df.Field.Type != "" {
+		fieldType = df.Field.Type
+	}
+
+	layout := UTCTIMESTAMPLAYOUT
+	switch fieldType {
+	case "UTCTIMEONLY", "TZTIMEONLY", "LOCALMKTTIME":
+		layout = UTCTIMEONLYLAYOUT
+	case "TZTIMESTAMP":
+	default:
+		fieldType = "UTCTIMESTAMP"
+	}
+
+	value, location := df.Value, time.UTC
+	if fieldType == "TZTIMESTAMP" || fieldType == "TZTIMEONLY" {
+		var ok bool
+		if value, location, ok = splitTimeZone(value, len(layout)-len("15:04:05")); !ok {
+			return time.Time{}, df.invalid(fieldType)
+		}
+
+		if len(value) == len(layout)-len(":05") {
+			// seconds are optional
+			layout = layout[:len(layout)-len(":05")]
+		}
+	}
+
+	fraction := ""
+	if i := strings.IndexByte(value, '.'); i >= 0 {
+		value, fraction = value[:i], value[i+1:]
+		switch len(fraction) {
+		case 3, 6, 9:
+		case 12:
+			fraction = fraction[:9]
+		default:
+			return time.Time{}, df.invalid(fieldType)
+		}
+
+		if !isDigits(fraction) {
+			return time.Time{}, df.invalid(fieldType)
+		}
+	}
+
+	if !matchesLayout(value, layout) {
+		return time.Time{}, df.invalid(fieldType)
+	}
+
+	t, err := time.ParseInLocation(layout, value, location)
+	if err != nil {
+		return time.Time{}, df.invalid(fieldType)
+	}
+
+	if fraction != "" {
+		nanoseconds, _ := strconv.Atoi(fraction + strings.Repeat("0", 9-len(fraction)))
+		t = t.Add(time.Duration(nanoseconds))
+	}
+
+	return t, nil
+}
+
+// splitTimeZone split the offset from UTC off a TZTIMESTAMP or TZTIMEONLY value: Z, +hh, -hh, +hh:mm or -hh:mm, looked for from start
+// (past the date of a TZTIMESTAMP). UTC if the value has no offset
+func splitTimeZone(value string, start int) (string, *time.Location, bool) {
+	if start > len(value) {
+		return value, nil, false
+	}
+
+	i := strings.IndexAny(value[start:], "Z+-")
+	if i < 0 {
+		return value, time.UTC, true
+	}
+
+	i += start
+	if value[i:] == "Z" {
+		return value[:i], time.UTC, true
+	}
+
+	zone := value[i+1:]
+	if !matchesLayout(zone, "15") && !matchesLayout(zone, "15:04") {
+		return value, nil, false
+	}
+
+	hours, _ := strconv.Atoi(zone[:2])
+	minutes := 0
+	if len(zone) > 2 {
+		minutes, _ = strconv.Atoi(zone[3:])
+	}
+
+	if hours > 14 || minutes > 59 {
+		return value, nil, false
+	}
+
+	offset := hours*3600 + minutes*60
+	if value[i] == '-' {
+		offset = -offset
+	}
+
+	return value[:i], time.FixedZone("", offset), true
+}
+
+// Date the value of a LOCALMKTDATE or UTCDATEONLY field, e.g. 20260301, at midnight UTC
+func (df *DecodedField) Date() (time.Time, error) {
+	fieldType := "LOCALMKTDATE"
+	if df.Field != nil && df.Field.Type != "" {
+		fieldType = df.Field.Type
+	}
+
+	if !matchesLayout(df.Value, DATELAYOUT) {
+		return time.Time{}, df.invalid(fieldType)
+	}
+
+	t, err := time.Parse(DATELAYOUT, df.Value)
+	if err != nil {
+		return time.Time{}, df.invalid(fieldType)
+	}
+
+	return t, nil
+}
+
+// MonthYear the value of a MONTHYEAR field
+func (df *DecodedField) MonthYear() (MonthYear, error) {
+	m, err := ParseMonthYear(df.Value)
+	if err != nil {
+		return MonthYear{}, df.invalid("MONTHYEAR")
+	}
+
+	return m, nil
+}
+
+// invalid the error of a value which does not conform to the type it was read as. The declared type of the field is reported too if known
+func (df *DecodedField) invalid(fieldType string) error {
+	err := &ErrInvalidValue{Tag: df.Tag, Type: fieldType, Value: df.Value}
+	if df.Field != nil {
+		err.FieldType = df.Field.Type
+	}
+
+	return err
+}
+
+// Field the field with the given tag according to the lookup policy, nil if there is none or if it is ambiguous, see Lookup
+func (m *Message) Field(tag int) *DecodedField {
+	df, _ := m.Lookup(tag)
+	return df
+}
+
+// GetInt the value of the field with the given tag as an int, per the lookup policy, see DecodedField.Int
+func (m *Message) GetInt(tag int) (int, error) {
+	df, err := m.Lookup(tag)
+	if err != nil {
+		return 0, err
+	}
+
+	return df.Int()
+}
+
+// GetDecimal the value of the field with the given tag as an exact decimal, per the lookup policy, see DecodedField.Decimal
+func (m *Message) GetDecimal(tag int) (Decimal, error) {
+	df, err := m.Lookup(tag)
+	if err != nil {
+		return Decimal{}, err
+	}
+
+	return df.Decimal()
+}
+
+// GetBool the value of the field with the given tag as a bool, per the lookup policy, see DecodedField.Bool
+func (m *Message) GetBool(tag int) (bool, error) {
+	df, err := m.Lookup(tag)
+	if err != nil {
+		return false, err
+	}
+
+	return df.Bool()
+}
+
+// GetTime the value of the field with the given tag as a time, per the lookup policy, see DecodedField.Time
+func (m *Message) GetTime(tag int) (time.Time, error) {
+	df, err := m.Lookup(tag)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return df.Time()
+}
+
+// GetDate the value of the field with the given tag as a date, per the lookup policy, see DecodedField.Date
+func (m *Message) GetDate(tag int) (time.Time, error) {
+	df, err := m.Lookup(tag)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return df.Date()
+}
+
+// GetMonthYear the value of the field with the given tag as a month year, per the lookup policy, see DecodedField.MonthYear
+func (m *Message) GetMonthYear(tag int) (MonthYear, error) {
+	df, err := m.Lookup(tag)
+	if err != nil {
+		return MonthYear{}, err
+	}
+
+	return df.MonthYear()
+}
+
+// matchesLayout whether a value has the digits and separators of a time layout, e.g. 20260301-10:00:00 for UTCTIMESTAMPLAYOUT.
+// time.Parse alone would accept one digit hours
+func matchesLayout(value, layout string) bool {
+	if len(value) != len(layout) {
+		return false
+	}
+
+	for i := 0; i < len(layout); i++ {
+		if isDigit(layout[i]) != isDigit(value[i]) || (!isDigit(layout[i]) && layout[i] != value[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isDigits whether s is made of ascii digits only, and not empty
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+
+	return true
+}
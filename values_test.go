@@ -0,0 +1,96 @@
+package fixdecoder_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	fixdecoder "github.com/ilovelili/FixDecoder"
+)
+
+func TestMessage_TypedAccessors(t *testing.T) {
+	msg, err := fd.DecodeE("8=FIX.4.4|9=0|35=8|34=12|44=12.50|32=-0.001|43=Y|52=20260301-10:00:00.123456789|75=20260301|200=202603w2|10=000|")
+	if err != nil {
+		t.Fatalf("expect no error, actual %v", err)
+	}
+
+	if seq, err := msg.GetInt(34); err != nil || seq != 12 {
+		t.Errorf("expect 12, actual %d %v", seq, err)
+	}
+
+	price, err := msg.GetDecimal(44)
+	if err != nil || price.String() != "12.50" || price.Scale() != 2 {
+		t.Errorf("expect 12.50, actual %s %v", price, err)
+	}
+
+	if twelve, _ := fixdecoder.ParseDecimal("12.5"); price.Cmp(twelve) != 0 {
+		t.Errorf("expect 12.50 equal to 12.5")
+	}
+
+	if qty, err := msg.GetDecimal(32); err != nil || qty.String() != "-0.001" || qty.Float64() != -0.001 {
+		t.Errorf("expect -0.001, actual %s %v", qty, err)
+	}
+
+	qty, _ := msg.GetDecimal(32)
+	if sum := price.Add(qty); sum.String() != "12.499" || sum.Sign() != 1 {
+		t.Errorf("expect 12.499, actual %s", sum)
+	}
+
+	if possDup, err := msg.GetBool(43); err != nil || !possDup {
+		t.Errorf("expect true, actual %v %v", possDup, err)
+	}
+
+	expect := time.Date(2026, 3, 1, 10, 0, 0, 123456789, time.UTC)
+	if sendingTime, err := msg.GetTime(52); err != nil || !sendingTime.Equal(expect) {
+		t.Errorf("expect %s, actual %s %v", expect, sendingTime, err)
+	}
+
+	if tradeDate, err := msg.GetDate(75); err != nil || !tradeDate.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expect 2026-03-01, actual %s %v", tradeDate, err)
+	}
+
+	if maturity, err := msg.GetMonthYear(200); err != nil || maturity.Year != 2026 || maturity.Month != time.March || maturity.Week != 2 || maturity.String() != "202603w2" {
+		t.Errorf("expect 202603w2, actual %+v %v", maturity, err)
+	}
+
+	var notFound *fixdecoder.ErrFieldNotFound
+	if _, err := msg.GetInt(38); !errors.As(err, &notFound) || notFound.Tag != 38 {
+		t.Errorf("expect field not found, actual %v", err)
+	}
+}
+
+func TestMessage_TypedAccessors_Invalid(t *testing.T) {
+	msg, _ := fd.DecodeE("8=FIX.4.4|9=0|35=8|34=+12|44=1e3|43=y|52=20260301-1:00:00|60=20260301-10:00:00.1234|75=20260231|200=202613|10=000|")
+
+	checks := []struct {
+		name      string
+		fieldType string
+		err       error
+	}{
+		{"INT", "SEQNUM", second(msg.GetInt(34))},
+		{"FLOAT", "PRICE", second(msg.GetDecimal(44))},
+		{"BOOLEAN", "BOOLEAN", second(msg.GetBool(43))},
+		{"UTCTIMESTAMP", "UTCTIMESTAMP", second(msg.GetTime(52))},
+		{"UTCTIMESTAMP", "UTCTIMESTAMP", second(msg.GetTime(60))},
+		{"LOCALMKTDATE", "LOCALMKTDATE", second(msg.GetDate(75))},
+		{"MONTHYEAR", "MONTHYEAR", second(msg.GetMonthYear(200))},
+	}
+
+	for _, check := range checks {
+		var invalid *fixdecoder.ErrInvalidValue
+		if !errors.As(check.err, &invalid) || invalid.Type != check.name || invalid.FieldType != check.fieldType {
+			t.Errorf("expect invalid %s value of a %s field, actual %v", check.name, check.fieldType, check.err)
+		}
+	}
+
+	msg, _ = fd.DecodeE("8=FIX.4.4|9=0|35=D|55=IBM|10=000|")
+	expect := `fixdecoder: invalid INT value "IBM" for STRING field 55`
+	if _, err := msg.GetInt(55); err == nil || err.Error() != expect {
+		t.Errorf("expect %s, actual %v", expect, err)
+	}
+}
+
+// second the error of a two values result
+func second(_ interface{}, err error) error {
+	return err
+}
@@ -0,0 +1,397 @@
+package fixdecoder
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+const (
+	// FIXT11 BeginString <8> of the FIXT.1.1 transport, used by FIX 5.0 and later. The application version is given by ApplVerID <1128>,
+	// or by the DefaultApplVerID <1137> agreed at Logon
+	FIXT11 = "FIXT.1.1"
+	// APPLVERID Specifies the service pack release being applied at message level. Enumerated field with values assigned at time of service pack release
+	APPLVERID = "1128"
+)
+
+// applVerIDs FIX versions group by ApplVerID <1128> / DefaultApplVerID <1137> value
+var applVerIDs = map[string]string{
+	"2": "FIX.4.0",
+	"3": "FIX.4.1",
+	"4": "FIX.4.2",
+	"5": "FIX.4.3",
+	"6": "FIX.4.4",
+	"7": "FIX.5.0",
+	"8": "FIX.5.0SP1",
+	"9": "FIX.5.0SP2",
+}
+
+// sessionMsgTypes session level messages. Since FIX 5.0 they belong to the FIXT.1.1 transport
+var sessionMsgTypes = []string{"0", "1", "2", "3", "4", "5", "A"}
+
+// builtinVersion a built-in dictionary derived from the FIX 4.4 one when the version has no generated data (see versionData): fields up to maxTag,
+// plus the fields introduced by the version and its predecessors
+type builtinVersion struct {
+	maxTag      int
+	fields      []*FieldDefinition
+	session     bool // whether the dictionary holds the session level messages
+	application bool // whether the dictionary holds the application level messages
+}
+
+// builtinVersions the versions with a built-in dictionary
+var builtinVersions = map[string]builtinVersion{
+	"FIX.4.0":    {maxTag: 140, session: true, application: true},
+	"FIX.4.1":    {maxTag: 211, session: true, application: true},
+	"FIX.4.2":    {maxTag: 446, session: true, application: true},
+	"FIX.4.3":    {maxTag: 659, session: true, application: true},
+	"FIX.4.4":    {maxTag: 956, session: true, application: true},
+	"FIX.5.0":    {maxTag: 956, fields: fix50Fields, application: true},
+	"FIX.5.0SP1": {maxTag: 956, fields: append(append([]*FieldDefinition{}, fix50Fields...), fix50SP1Fields...), application: true},
+	"FIX.5.0SP2": {maxTag: 956, fields: append(append(append([]*FieldDefinition{}, fix50Fields...), fix50SP1Fields...), fix50SP2Fields...), application: true},
+	FIXT11:       {maxTag: 956, fields: fixt11Fields, session: true},
+}
+
+// applVerIDValues values of ApplVerID <1128>, DefaultApplVerID <1137> and RefApplVerID <1130>
+var applVerIDValues = map[string]string{
+	"0": "FIX27",
+	"1": "FIX30",
+	"2": "FIX40",
+	"3": "FIX41",
+	"4": "FIX42",
+	"5": "FIX43",
+	"6": "FIX44",
+	"7": "FIX50",
+	"8": "FIX50SP1",
+	"9": "FIX50SP2",
+}
+
+// fixt11Fields fields introduced by FIXT.1.1
+var fixt11Fields = []*FieldDefinition{
+	{Tag: "1128", Name: "ApplVerID", Type: "STRING", Values: applVerIDValues, IsHeaderField: true},
+	{Tag: "1129", Name: "CstmApplVerID", Type: "STRING", IsHeaderField: true},
+	{Tag: "1130", Name: "RefApplVerID", Type: "STRING", Values: applVerIDValues},
+	{Tag: "1131", Name: "RefCstmApplVerID", Type: "STRING"},
+	{Tag: "1137", Name: "DefaultApplVerID", Type: "STRING", Values: applVerIDValues},
+	{Tag: "1156", Name: "ApplExtID", Type: "INT", IsHeaderField: true},
+}
+
+// fix50Fields fields introduced by FIX 5.0
+var fix50Fields = append(append([]*FieldDefinition{}, fixt11Fields...), []*FieldDefinition{
+	{Tag: "1003", Name: "TradeID", Type: "STRING"},
+	{Tag: "1021", Name: "MDBookType", Type: "INT", Values: map[string]string{"1": "Top Of Book", "2": "Price Depth", "3": "Order Depth"}},
+	{Tag: "1022", Name: "MDFeedType", Type: "STRING"},
+	{Tag: "1023", Name: "MDPriceLevel", Type: "INT"},
+	{Tag: "1024", Name: "MDOriginType", Type: "INT", Values: map[string]string{"0": "Book", "1": "Off Book", "2": "Cross"}},
+	{Tag: "1028", Name: "ManualOrderIndicator", Type: "BOOLEAN"},
+	{Tag: "1029", Name: "CustDirectedOrder", Type: "BOOLEAN"},
+	{Tag: "1031", Name: "CustOrderHandlingInst", Type: "MULTIPLESTRINGVALUE"},
+	{Tag: "1032", Name: "OrderHandlingInstSource", Type: "INT"},
+	{Tag: "1057", Name: "AggressorIndicator", Type: "BOOLEAN"},
+	{Tag: "1070", Name: "MDQuoteType", Type: "INT", Values: map[string]string{"0": "Indicative", "1": "Tradeable", "2": "Restricted Tradeable", "3": "Counter", "4": "Indicative And Tradeable"}},
+	{Tag: "1080", Name: "RefOrderID", Type: "STRING"},
+	{Tag: "1081", Name: "RefOrderIDSource", Type: "CHAR"},
+	{Tag: "1084", Name: "DisplayMethod", Type: "CHAR"},
+	{Tag: "1089", Name: "MatchIncrement", Type: "QTY"},
+	{Tag: "1090", Name: "MaxPriceLevels", Type: "INT"},
+	{Tag: "1091", Name: "PreTradeAnonymity", Type: "BOOLEAN"},
+	{Tag: "1093", Name: "LotType", Type: "CHAR"},
+	{Tag: "1094", Name: "PegPriceType", Type: "INT"},
+	{Tag: "1133", Name: "ExDestinationIDSource", Type: "CHAR"},
+	{Tag: "1138", Name: "DisplayQty", Type: "QTY"},
+}...)
+
+// fix50SP1Fields fields introduced by FIX 5.0 SP1
+var fix50SP1Fields = []*FieldDefinition{
+	{Tag: "1151", Name: "SecurityGroup", Type: "STRING"},
+	{Tag: "1180", Name: "ApplID", Type: "STRING"},
+	{Tag: "1181", Name: "ApplSeqNum", Type: "SEQNUM"},
+	{Tag: "1182", Name: "ApplBegSeqNum", Type: "SEQNUM"},
+	{Tag: "1183", Name: "ApplEndSeqNum", Type: "SEQNUM"},
+	{Tag: "1300", Name: "MarketSegmentID", Type: "STRING"},
+	{Tag: "1301", Name: "MarketID", Type: "EXCHANGE"},
+	{Tag: "1346", Name: "ApplReqID", Type: "STRING"},
+	{Tag: "1350", Name: "ApplLastSeqNum", Type: "SEQNUM"},
+	{Tag: "1352", Name: "ApplResendFlag", Type: "BOOLEAN"},
+}
+
+// fix50SP2Fields fields introduced by FIX 5.0 SP2
+var fix50SP2Fields = []*FieldDefinition{
+	{Tag: "1377", Name: "MultilegModel", Type: "INT"},
+	{Tag: "1378", Name: "MultilegPriceMethod", Type: "INT"},
+	{Tag: "1500", Name: "MDStreamID", Type: "STRING"},
+}
+
+//go:generate go run ./cmd/fixdictgen --spec quickfix/spec --output versions_data.go
+
+// versionData the dictionaries of the versions in the JSON format of the built-in one, generated from the QuickFIX specs by
+// cmd/fixdictgen into versions_data.go (make dictionaries). Versions without generated data are derived from FIX 4.4, see builtinVersions
+var versionData = map[string]string{}
+
+var (
+	versionDictionaries     = make(map[string]*Dictionary)
+	versionDictionariesLock sync.Mutex
+)
+
+// VersionDictionary get the dictionary of a FIX version: FIX.4.0 to FIX.4.4, FIX.5.0, FIX.5.0SP1, FIX.5.0SP2 or FIXT.1.1, or any version
+// registered with RegisterVersionDictionary. The dictionaries of the versions are generated from the QuickFIX specs (see versionData);
+// without generated data, a version is derived from the FIX 4.4 dictionary with its own copy of the field definitions.
+// nil if the version is unknown
+func VersionDictionary(version string) *Dictionary {
+	versionDictionariesLock.Lock()
+	defer versionDictionariesLock.Unlock()
+
+	if d, found := versionDictionaries[version]; found {
+		return d
+	}
+
+	if version == "FIX.4.4" {
+		return DefaultDictionary()
+	}
+
+	if data, found := versionData[version]; found {
+		d, err := LoadJSONDictionary(data)
+		if err == nil {
+			d.Version = version
+			versionDictionaries[version] = d
+			return d
+		}
+	}
+
+	builtin, found := builtinVersions[version]
+	if !found {
+		return nil
+	}
+
+	d := deriveDictionary(DefaultDictionary(), version, builtin)
+	versionDictionaries[version] = d
+	return d
+}
+
+// RegisterVersionDictionary replace the dictionary of its version (e.g. FIX.4.2) for all decoders and validators, or add a version
+func RegisterVersionDictionary(d *Dictionary) {
+	versionDictionariesLock.Lock()
+	defer versionDictionariesLock.Unlock()
+
+	versionDictionaries[d.Version] = d
+}
+
+// LoadQuickFIXVersionDictionaries load the QuickFIX dictionaries of a directory, e.g. FIX42.xml, FIX50SP2.xml and FIXT11.xml from the spec directory
+// of a QuickFIX engine, and register them with RegisterVersionDictionary. Returns the versions registered
+func LoadQuickFIXVersionDictionaries(dir string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "FIX*.xml"))
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]string, 0, len(paths))
+	for _, path := range paths {
+		d, err := LoadQuickFIXDictionaryFile(path)
+		if err != nil {
+			return versions, err
+		}
+
+		RegisterVersionDictionary(d)
+		versions = append(versions, d.Version)
+	}
+
+	if len(versions) == 0 {
+		return versions, fmt.Errorf("fixdecoder: no QuickFIX dictionary in %s", dir)
+	}
+
+	return versions, nil
+}
+
+// ApplVerIDVersion get the FIX version of an ApplVerID <1128> value, e.g. FIX.5.0SP2 for 9. Empty if unknown
+func ApplVerIDVersion(applVerID string) string {
+	return applVerIDs[applVerID]
+}
+
+// SelectDictionary pick the built-in dictionary of a message from its BeginString <8> and, for FIXT.1.1, its ApplVerID <1128>
+// (defaultApplVerID if absent). Falls back to the FIX 4.4 dictionary
+func SelectDictionary(beginString, applVerID, defaultApplVerID string) *Dictionary {
+	return selectDictionary(VersionDictionary, beginString, applVerID, defaultApplVerID)
+}
+
+// selectDictionary pick the dictionary of a message, looking up dictionaries by version with lookup
+func selectDictionary(lookup func(string) *Dictionary, beginString, applVerID, defaultApplVerID string) *Dictionary {
+	if beginString != FIXT11 {
+		if d := lookup(beginString); d != nil {
+			return d
+		}
+		return DefaultDictionary()
+	}
+
+	if applVerID == "" {
+		applVerID = defaultApplVerID
+	}
+
+	transport := lookup(FIXT11)
+	application := lookup(ApplVerIDVersion(applVerID))
+	if application == nil {
+		application = lookup("FIX.5.0SP2")
+	}
+
+	if transport == nil || application == nil {
+		return DefaultDictionary()
+	}
+
+	return composeFIXT(transport, application)
+}
+
+var (
+	fixtDictionaries     = make(map[[2]*Dictionary]*Dictionary)
+	fixtDictionariesLock sync.Mutex
+)
+
+// composeFIXT the dictionary of a FIXT.1.1 message: header, trailer and session level messages come from the transport dictionary,
+// application messages from the application dictionary
+func composeFIXT(transport, application *Dictionary) *Dictionary {
+	fixtDictionariesLock.Lock()
+	defer fixtDictionariesLock.Unlock()
+
+	key := [2]*Dictionary{transport, application}
+	if d, found := fixtDictionaries[key]; found {
+		return d
+	}
+
+	d := NewDictionary(application.Version)
+	d.SystemFieldIDs = transport.SystemFieldIDs
+	d.Header = transport.Header
+	d.Trailer = transport.Trailer
+
+	for _, field := range application.Fields {
+		d.AddField(field)
+	}
+
+	for _, field := range transport.Fields {
+		if field.IsHeaderField || d.Field(field.Tag) == nil {
+			d.AddField(field)
+		}
+	}
+
+	for tag, group := range application.Groups {
+		d.Groups[tag] = group
+	}
+
+	for tag, group := range transport.Groups {
+		if _, found := d.Groups[tag]; !found {
+			d.Groups[tag] = group
+		}
+	}
+
+	for name, component := range application.Components {
+		d.Components[name] = component
+	}
+
+	for msgType, message := range application.Messages {
+		d.Messages[msgType] = message
+	}
+
+	for _, msgType := range sessionMsgTypes {
+		if message := transport.Message(msgType); message != nil {
+			d.Messages[msgType] = message
+		}
+	}
+
+	fixtDictionaries[key] = d
+	return d
+}
+
+// deriveDictionary derive the dictionary of a version from base: fields above maxTag are dropped, the fields of the version are added,
+// and the session level messages are kept or dropped depending on whether they belong to the version. Field definitions are copied, so that
+// changing the definition of a version leaves the other versions untouched
+func deriveDictionary(base *Dictionary, version string, builtin builtinVersion) *Dictionary {
+	d := NewDictionary(version)
+	d.SystemFieldIDs = base.SystemFieldIDs
+
+	known := func(tag string) bool {
+		number, err := strconv.Atoi(tag)
+		return err == nil && number <= builtin.maxTag
+	}
+
+	filter := func(tags []string) []string {
+		result := make([]string, 0, len(tags))
+		for _, tag := range tags {
+			if known(tag) || d.Field(tag) != nil {
+				result = append(result, tag)
+			}
+		}
+		return result
+	}
+
+	for tag, field := range base.Fields {
+		if known(tag) {
+			d.AddField(copyField(field))
+		}
+	}
+
+	for _, field := range builtin.fields {
+		d.AddField(copyField(field))
+	}
+
+	for tag, group := range base.Groups {
+		if known(tag) {
+			d.Groups[tag] = &GroupDefinition{Fields: filter(group.Fields)}
+		}
+	}
+
+	definition := func(definition *Definition) *Definition {
+		return &Definition{
+			Name:               definition.Name,
+			Fields:             filter(definition.Fields),
+			Required:           filter(definition.Required),
+			Components:         definition.Components,
+			RequiredComponents: definition.RequiredComponents,
+		}
+	}
+
+	for name, component := range base.Components {
+		d.Components[name] = definition(component)
+	}
+
+	session := make(map[string]bool)
+	for _, msgType := range sessionMsgTypes {
+		session[msgType] = true
+	}
+
+	for msgType, message := range base.Messages {
+		if (session[msgType] && builtin.session) || (!session[msgType] && builtin.application) {
+			d.Messages[msgType] = definition(message)
+		}
+	}
+
+	d.Header = definition(base.Header)
+	d.Trailer = definition(base.Trailer)
+
+	if version == FIXT11 {
+		d.Header.Fields = append(d.Header.Fields, "1128", "1129", "1156")
+		logon := d.Messages["A"]
+		logon.Fields = append(logon.Fields, "1137")
+		logon.Required = append(logon.Required, "1137")
+	}
+
+	if builtin.maxTag < 659 {
+		// ExecTransType <20> was required in execution reports until FIX 4.3
+		if executionReport := d.Messages["8"]; executionReport != nil {
+			executionReport.Fields = append([]string{"20"}, executionReport.Fields...)
+			executionReport.Required = append([]string{"20"}, executionReport.Required...)
+		}
+	}
+
+	return d
+}
+
+// copyField a copy of a field definition, enumerated values included
+func copyField(field *FieldDefinition) *FieldDefinition {
+	result := *field
+	result.metaData = nil
+	if field.Values != nil {
+		result.Values = make(map[string]string, len(field.Values))
+		for value, description := range field.Values {
+			result.Values[value] = description
+		}
+	}
+
+	return &result
+}